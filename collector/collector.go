@@ -21,12 +21,24 @@ package collector
 
 import (
 	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	log "github.com/Sirupsen/logrus"
 	openstackintel "github.com/intelsdi-x/snap-plugin-collector-neutron/openstack"
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/openstack/agents"
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/openstack/ipavailability"
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/openstack/loadbalancers"
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/openstack/tenantquotas"
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/openstack/tenantresources"
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/redact"
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/types"
 	openstackgophercloud "github.com/rackspace/gophercloud/openstack"
 
 	"github.com/intelsdi-x/snap-plugin-utilities/config"
@@ -56,15 +68,129 @@ const (
 	//nsLength length of namespace
 	nsLength = 5
 
+	//resourceNSLength length of namespace of metrics of single network or router
+	resourceNSLength = 7
+
+	//subnetNSLength length of namespace of subnet metrics
+	subnetNSLength = 9
+
+	//agentNSLength length of namespace of metrics of single agent
+	agentNSLength = 7
+
+	//agentTypeNSLength length of namespace of metrics of agent type
+	agentTypeNSLength = 6
+
 	//quotaNameIdx position of quota prefix in metric name
 	quotaNameIdx = 1
 
 	//metricNameNSPartNumber position of metric name in namespace
 	metricNameNSPartNumber = 4
 
-	//tenantNameNSPartNumber position of tenant name in namespace
+	//tenantNameNSPartNumber position of tenant name (or tenant ID) in namespace
 	tenantNameNSPartNumber = 3
 
+	//resourceNSPartNumber position of collection (networks or routers) in namespace of metrics of single network, subnet or router
+	resourceNSPartNumber = 4
+
+	//resourceIDNSPartNumber position of network ID or router ID in namespace of metrics of single network, subnet or router
+	resourceIDNSPartNumber = 5
+
+	//subnetsNSPartNumber position of subnets namespace part in subnet metrics
+	subnetsNSPartNumber = 6
+
+	//subnetIDNSPartNumber position of subnet ID in subnet metrics
+	subnetIDNSPartNumber = 7
+
+	//networkNSPart namespace part which precedes network ID
+	networkNSPart = "network"
+
+	//subnetsNSPart namespace part which precedes subnet ID
+	subnetsNSPart = "subnets"
+
+	//routerNSPart namespace part which precedes router ID
+	routerNSPart = "router"
+
+	//subnetPoolsNSPart namespace part which precedes subnet pool ID
+	subnetPoolsNSPart = "subnetpools"
+
+	//agentsNSPart namespace part which replaces tenant name in metrics of Neutron agents, agents are not owned by tenants
+	agentsNSPart = "_agents"
+
+	//agentTypeNSPartNumber position of agent type in namespace of agent metrics
+	agentTypeNSPartNumber = 4
+
+	//hostNSPartNumber position of host in namespace of metrics of single agent
+	hostNSPartNumber = 5
+
+	//agentTypeElement name of dynamic namespace element which holds type of agent
+	agentTypeElement = "agent_type"
+
+	//hostElement name of dynamic namespace element which holds host of agent
+	hostElement = "host"
+
+	//agentAliveMetric name of metric which indicates whether agent is alive
+	agentAliveMetric = "alive"
+
+	//agentAdminStateUpMetric name of metric which indicates whether agent is administratively enabled
+	agentAdminStateUpMetric = "admin_state_up"
+
+	//agentHeartbeatAgeMetric name of metric which indicates number of seconds since the last heartbeat of agent
+	agentHeartbeatAgeMetric = "heartbeat_age"
+
+	//agentsAliveCountMetric name of metric which indicates number of alive agents of given type
+	agentsAliveCountMetric = "alive_count"
+
+	//agentsDownCountMetric name of metric which indicates number of down agents of given type
+	agentsDownCountMetric = "down_count"
+
+	//networkIDElement name of dynamic namespace element which holds network ID
+	networkIDElement = "network_id"
+
+	//subnetIDElement name of dynamic namespace element which holds subnet ID
+	subnetIDElement = "subnet_id"
+
+	//routerIDElement name of dynamic namespace element which holds router ID
+	routerIDElement = "router_id"
+
+	//subnetPoolIDElement name of dynamic namespace element which holds subnet pool ID
+	subnetPoolIDElement = "subnetpool_id"
+
+	//prefixCapacityMetric name of metric which indicates number of addresses of subnet pool prefixes
+	prefixCapacityMetric = "prefix_capacity"
+
+	//prefixFreeMetric name of metric which indicates number of addresses of subnet pool which are not allocated to subnets
+	prefixFreeMetric = "prefix_free"
+
+	//prefixUtilizationMetric name of metric which indicates percentage of addresses of subnet pool allocated to subnets
+	prefixUtilizationMetric = "prefix_utilization"
+
+	//interfacesCountMetric name of metric which indicates number of router interfaces
+	interfacesCountMetric = "interfaces_count"
+
+	//ipTotalMetric name of metric which indicates number of allocatable IP addresses
+	ipTotalMetric = "ip_total"
+
+	//ipUsedMetric name of metric which indicates number of used IP addresses
+	ipUsedMetric = "ip_used"
+
+	//ipUtilizationMetric name of metric which indicates percentage of used IP addresses
+	ipUtilizationMetric = "ip_utilization"
+
+	//tenantNameElement name of dynamic namespace element which holds tenant name
+	tenantNameElement = "tenant_name"
+
+	//tenantIDElement name of dynamic namespace element which holds tenant ID
+	tenantIDElement = "tenant_id"
+
+	//tenantWildcard value of tenant namespace element which matches all tenants
+	tenantWildcard = "*"
+
+	//tenantNamespaceName value of cfgTenantNamespace which means that tenants are identified in namespace by name
+	tenantNamespaceName = "name"
+
+	//tenantNamespaceID value of cfgTenantNamespace which means that tenants are identified in namespace by ID
+	tenantNamespaceID = "id"
+
 	//networksCountMetric name of metric which indicates  number of tenant networks
 	networksCountMetric = "networks_count"
 
@@ -77,12 +203,189 @@ const (
 	//portsCountMetric name of metric which indicates  number of tenant ports
 	portsCountMetric = "ports_count"
 
+	//portsActiveCountMetric name of metric which indicates number of tenant ports in ACTIVE status
+	portsActiveCountMetric = "ports_active_count"
+
+	//portsDownCountMetric name of metric which indicates number of tenant ports in DOWN status
+	portsDownCountMetric = "ports_down_count"
+
+	//portsBuildCountMetric name of metric which indicates number of tenant ports in BUILD status
+	portsBuildCountMetric = "ports_build_count"
+
+	//portsErrorCountMetric name of metric which indicates number of tenant ports in ERROR status
+	portsErrorCountMetric = "ports_error_count"
+
+	//portsComputeCountMetric name of metric which indicates number of tenant ports of instances (device owner compute:*)
+	portsComputeCountMetric = "ports_compute_count"
+
+	//portsRouterInterfaceCountMetric name of metric which indicates number of tenant router interface ports
+	portsRouterInterfaceCountMetric = "ports_router_interface_count"
+
+	//portsDHCPCountMetric name of metric which indicates number of tenant DHCP ports
+	portsDHCPCountMetric = "ports_dhcp_count"
+
+	//portsFloatingIPCountMetric name of metric which indicates number of tenant floating IP ports
+	portsFloatingIPCountMetric = "ports_floatingip_count"
+
+	//portsUnboundCountMetric name of metric which indicates number of tenant ports without device owner
+	portsUnboundCountMetric = "ports_unbound_count"
+
+	//portsOtherCountMetric name of metric which indicates number of tenant ports with device owner of no other class (e.g. router gateways)
+	portsOtherCountMetric = "ports_other_count"
+
+	//portsQoSPolicyCountMetric name of metric which indicates number of tenant ports with QoS policy attached
+	portsQoSPolicyCountMetric = "ports_with_qos_policy_count"
+
+	//networksQoSPolicyCountMetric name of metric which indicates number of tenant networks with QoS policy attached
+	networksQoSPolicyCountMetric = "networks_with_qos_policy_count"
+
+	//qosPoliciesCountMetric name of metric which indicates number of tenant QoS policies
+	qosPoliciesCountMetric = "qos_policies_count"
+
+	//qosRulesCountMetric name of metric which indicates number of rules of tenant QoS policies
+	qosRulesCountMetric = "qos_rules_count"
+
+	//qosRulesBandwidthLimitCountMetric name of metric which indicates number of bandwidth_limit rules of tenant QoS policies
+	qosRulesBandwidthLimitCountMetric = "qos_rules_bandwidth_limit_count"
+
+	//qosRulesDSCPMarkingCountMetric name of metric which indicates number of dscp_marking rules of tenant QoS policies
+	qosRulesDSCPMarkingCountMetric = "qos_rules_dscp_marking_count"
+
+	//qosRulesMinimumBandwidthCountMetric name of metric which indicates number of minimum_bandwidth rules of tenant QoS policies
+	qosRulesMinimumBandwidthCountMetric = "qos_rules_minimum_bandwidth_count"
+
 	//floatingipsCountMetric name of metric which indicates  number of tenant  floating IPs
 	floatingipsCountMetric = "floatingips_count"
 
+	//floatingipsAssociatedCountMetric name of metric which indicates number of tenant floating IPs associated with port
+	floatingipsAssociatedCountMetric = "floatingips_associated_count"
+
+	//floatingipsUnassociatedCountMetric name of metric which indicates number of tenant floating IPs allocated but not associated with any port
+	floatingipsUnassociatedCountMetric = "floatingips_unassociated_count"
+
+	//floatingipsActiveCountMetric name of metric which indicates number of tenant floating IPs in ACTIVE status
+	floatingipsActiveCountMetric = "floatingips_active_count"
+
+	//floatingipsDownCountMetric name of metric which indicates number of tenant floating IPs in DOWN status
+	floatingipsDownCountMetric = "floatingips_down_count"
+
+	//floatingipsErrorCountMetric name of metric which indicates number of tenant floating IPs in ERROR status
+	floatingipsErrorCountMetric = "floatingips_error_count"
+
+	//securityGroupsCountMetric name of metric which indicates  number of tenant security groups
+	securityGroupsCountMetric = "security_groups_count"
+
+	//securityGroupRulesCountMetric name of metric which indicates  number of tenant security group rules
+	securityGroupRulesCountMetric = "security_group_rules_count"
+
+	//rbacPoliciesCountMetric name of metric which indicates number of tenant RBAC policies
+	rbacPoliciesCountMetric = "rbac_policies_count"
+
+	//rbacPoliciesNetworkCountMetric name of metric which indicates number of tenant RBAC policies which share networks
+	rbacPoliciesNetworkCountMetric = "rbac_policies_network_count"
+
+	//rbacPoliciesQoSPolicyCountMetric name of metric which indicates number of tenant RBAC policies which share QoS policies
+	rbacPoliciesQoSPolicyCountMetric = "rbac_policies_qos_policy_count"
+
+	//rbacPoliciesSecurityGroupCountMetric name of metric which indicates number of tenant RBAC policies which share security groups
+	rbacPoliciesSecurityGroupCountMetric = "rbac_policies_security_group_count"
+
+	//rbacPoliciesAddressScopeCountMetric name of metric which indicates number of tenant RBAC policies which share address scopes
+	rbacPoliciesAddressScopeCountMetric = "rbac_policies_address_scope_count"
+
+	//rbacPoliciesSubnetPoolCountMetric name of metric which indicates number of tenant RBAC policies which share subnet pools
+	rbacPoliciesSubnetPoolCountMetric = "rbac_policies_subnetpool_count"
+
+	//rbacPoliciesAddressGroupCountMetric name of metric which indicates number of tenant RBAC policies which share address groups
+	rbacPoliciesAddressGroupCountMetric = "rbac_policies_address_group_count"
+
+	//rbacPoliciesSharedCountMetric name of metric which indicates number of tenant RBAC policies with access_as_shared action
+	rbacPoliciesSharedCountMetric = "rbac_policies_access_as_shared_count"
+
+	//rbacPoliciesExternalCountMetric name of metric which indicates number of tenant RBAC policies with access_as_external action
+	rbacPoliciesExternalCountMetric = "rbac_policies_access_as_external_count"
+
+	//subnetPoolsCountMetric name of metric which indicates number of tenant subnet pools
+	subnetPoolsCountMetric = "subnetpools_count"
+
+	//firewallGroupsCountMetric name of metric which indicates number of tenant firewall groups
+	firewallGroupsCountMetric = "firewall_groups_count"
+
+	//firewallGroupsActiveCountMetric name of metric which indicates number of tenant firewall groups in ACTIVE status
+	firewallGroupsActiveCountMetric = "firewall_groups_active_count"
+
+	//firewallGroupsInactiveCountMetric name of metric which indicates number of tenant firewall groups in INACTIVE status
+	firewallGroupsInactiveCountMetric = "firewall_groups_inactive_count"
+
+	//firewallGroupsDownCountMetric name of metric which indicates number of tenant firewall groups in DOWN status
+	firewallGroupsDownCountMetric = "firewall_groups_down_count"
+
+	//firewallGroupsErrorCountMetric name of metric which indicates number of tenant firewall groups in ERROR status
+	firewallGroupsErrorCountMetric = "firewall_groups_error_count"
+
+	//firewallGroupsPendingCountMetric name of metric which indicates number of tenant firewall groups in PENDING_* status
+	firewallGroupsPendingCountMetric = "firewall_groups_pending_count"
+
+	//firewallGroupPortsCountMetric name of metric which indicates number of router ports bound to tenant firewall groups
+	firewallGroupPortsCountMetric = "firewall_group_ports_count"
+
+	//firewallPoliciesCountMetric name of metric which indicates number of tenant firewall policies
+	firewallPoliciesCountMetric = "firewall_policies_count"
+
+	//firewallRulesCountMetric name of metric which indicates number of tenant firewall rules
+	firewallRulesCountMetric = "firewall_rules_count"
+
+	//ikePoliciesCountMetric name of metric which indicates number of tenant VPN IKE policies
+	ikePoliciesCountMetric = "ikepolicies_count"
+
+	//ipsecPoliciesCountMetric name of metric which indicates number of tenant VPN IPsec policies
+	ipsecPoliciesCountMetric = "ipsecpolicies_count"
+
+	//vpnServicesCountMetric name of metric which indicates number of tenant VPN services
+	vpnServicesCountMetric = "vpnservices_count"
+
+	//ipsecSiteConnectionsCountMetric name of metric which indicates number of tenant IPsec site connections
+	ipsecSiteConnectionsCountMetric = "ipsec_site_connections_count"
+
+	//ipsecSiteConnectionsActiveCountMetric name of metric which indicates number of tenant IPsec site connections in ACTIVE status
+	ipsecSiteConnectionsActiveCountMetric = "ipsec_site_connections_active_count"
+
+	//ipsecSiteConnectionsDownCountMetric name of metric which indicates number of tenant IPsec site connections in DOWN status
+	ipsecSiteConnectionsDownCountMetric = "ipsec_site_connections_down_count"
+
+	//ipsecSiteConnectionsErrorCountMetric name of metric which indicates number of tenant IPsec site connections in ERROR status
+	ipsecSiteConnectionsErrorCountMetric = "ipsec_site_connections_error_count"
+
+	//countSuffix suffix of metrics which indicate number of tenant resources
+	countSuffix = "_count"
+
+	//errorsNSPart namespace part which replaces tenant name in metrics which indicate failed collection of resource family
+	errorsNSPart = "_errors"
+
+	//aggregateNSPart namespace part which replaces tenant name in metrics aggregated over whole cloud
+	aggregateNSPart = "_all"
+
+	//orphaned prefix for aggregate metrics which indicate number of resources owned by tenants which do not exist in Keystone
+	orphaned = "orphaned_"
+
 	//quotas prefix for quota metrics
 	quotas = "quotas_"
 
+	//quotaUsedSuffix suffix for quota metrics which indicate number of used resources
+	quotaUsedSuffix = "_used"
+
+	//quotaReservedSuffix suffix for quota metrics which indicate number of reserved resources
+	quotaReservedSuffix = "_reserved"
+
+	//utilization prefix for metrics which indicate ratio of used resources to quota
+	utilization = "utilization_"
+
+	//headroom prefix for metrics which indicate number of resources which still can be created
+	headroom = "headroom_"
+
+	//unlimitedQuota value of quota which means no limit
+	unlimitedQuota = -1
+
 	//cfgUrl name of configuration variable for url  for OpenStack Identity endpoint
 	cfgURL = "openstack_auth_url"
 
@@ -94,6 +397,51 @@ const (
 
 	//cfgTenant tenant name used to authenticate
 	cfgTenant = "openstack_tenant"
+
+	//cfgDomainName domain name used to authenticate with Identity API v3
+	cfgDomainName = "domain_name"
+
+	//cfgDomainID domain ID used to authenticate with Identity API v3
+	cfgDomainID = "domain_id"
+
+	//cfgAppCredentialID ID of application credential used to authenticate
+	cfgAppCredentialID = "application_credential_id"
+
+	//cfgAppCredentialName name of application credential used to authenticate
+	cfgAppCredentialName = "application_credential_name"
+
+	//cfgAppCredentialSecret secret of application credential used to authenticate
+	cfgAppCredentialSecret = "application_credential_secret"
+
+	//cfgPageSize maximum number of resources retrieved from Neutron in a single request
+	cfgPageSize = "page_size"
+
+	//cfgTenantIDFieldsOnly indicates whether only IDs and tenant IDs of resources are retrieved from Neutron
+	cfgTenantIDFieldsOnly = "tenant_id_fields_only"
+
+	//cfgTenantNamespace indicates whether tenants are identified in namespace by name or by ID
+	cfgTenantNamespace = "tenant_namespace"
+
+	//cfgTenantInclude regular expression which has to match names of monitored tenants
+	cfgTenantInclude = "tenant_include"
+
+	//cfgTenantExclude regular expression which excludes tenants with matching names from monitoring
+	cfgTenantExclude = "tenant_exclude"
+
+	//cfgDomainFilter regular expression which has to match domain IDs of monitored projects (Identity API v3 only)
+	cfgDomainFilter = "domain_filter"
+
+	//cfgLogOrphanedResources indicates whether IDs of resources owned by tenants which do not exist in Keystone are logged
+	cfgLogOrphanedResources = "log_orphaned_resources"
+
+	//cfgMaxConcurrentRequests maximum number of parallel per-tenant (and per-pool) requests sent to Neutron
+	cfgMaxConcurrentRequests = "max_concurrent_requests"
+
+	//defaultPageSize default number of resources retrieved from Neutron in a single request
+	defaultPageSize = 1000
+
+	//defaultMaxConcurrentRequests default number of parallel per-tenant requests sent to Neutron
+	defaultMaxConcurrentRequests = 10
 )
 
 //neutronConstMetrics slice of constant metric names
@@ -102,7 +450,352 @@ var neutronConstMetrics = []string{
 	subnetsCountMetric,
 	routersCountMetric,
 	portsCountMetric,
+	portsActiveCountMetric,
+	portsDownCountMetric,
+	portsBuildCountMetric,
+	portsErrorCountMetric,
+	portsComputeCountMetric,
+	portsRouterInterfaceCountMetric,
+	portsDHCPCountMetric,
+	portsFloatingIPCountMetric,
+	portsUnboundCountMetric,
+	portsOtherCountMetric,
+	portsQoSPolicyCountMetric,
+	networksQoSPolicyCountMetric,
 	floatingipsCountMetric,
+	floatingipsAssociatedCountMetric,
+	floatingipsUnassociatedCountMetric,
+	floatingipsActiveCountMetric,
+	floatingipsDownCountMetric,
+	floatingipsErrorCountMetric,
+	securityGroupsCountMetric,
+	securityGroupRulesCountMetric,
+	ikePoliciesCountMetric,
+	ipsecPoliciesCountMetric,
+	vpnServicesCountMetric,
+	ipsecSiteConnectionsCountMetric,
+	ipsecSiteConnectionsActiveCountMetric,
+	ipsecSiteConnectionsDownCountMetric,
+	ipsecSiteConnectionsErrorCountMetric,
+	rbacPoliciesCountMetric,
+	rbacPoliciesNetworkCountMetric,
+	rbacPoliciesQoSPolicyCountMetric,
+	rbacPoliciesSecurityGroupCountMetric,
+	rbacPoliciesAddressScopeCountMetric,
+	rbacPoliciesSubnetPoolCountMetric,
+	rbacPoliciesAddressGroupCountMetric,
+	rbacPoliciesSharedCountMetric,
+	rbacPoliciesExternalCountMetric,
+	subnetPoolsCountMetric,
+	qosPoliciesCountMetric,
+	qosRulesCountMetric,
+	qosRulesBandwidthLimitCountMetric,
+	qosRulesDSCPMarkingCountMetric,
+	qosRulesMinimumBandwidthCountMetric,
+	firewallGroupsCountMetric,
+	firewallGroupsActiveCountMetric,
+	firewallGroupsInactiveCountMetric,
+	firewallGroupsDownCountMetric,
+	firewallGroupsErrorCountMetric,
+	firewallGroupsPendingCountMetric,
+	firewallGroupPortsCountMetric,
+	firewallPoliciesCountMetric,
+	firewallRulesCountMetric,
+}
+
+//portsStatusMetrics maps metrics which indicate number of ports in given status to the status
+var portsStatusMetrics = map[string]string{
+	portsActiveCountMetric: "ACTIVE",
+	portsDownCountMetric:   "DOWN",
+	portsBuildCountMetric:  "BUILD",
+	portsErrorCountMetric:  "ERROR",
+}
+
+//portsOwnerMetrics maps metrics which indicate number of ports with given class of device owner to the class
+var portsOwnerMetrics = map[string]string{
+	portsComputeCountMetric:         openstackintel.PortOwnerCompute,
+	portsRouterInterfaceCountMetric: openstackintel.PortOwnerRouterInterface,
+	portsDHCPCountMetric:            openstackintel.PortOwnerDHCP,
+	portsFloatingIPCountMetric:      openstackintel.PortOwnerFloatingIP,
+	portsUnboundCountMetric:         openstackintel.PortOwnerUnbound,
+	portsOtherCountMetric:           openstackintel.PortOwnerOther,
+}
+
+//floatingipsStatusMetrics maps metrics which indicate number of floating IPs in given status to the status
+var floatingipsStatusMetrics = map[string]string{
+	floatingipsActiveCountMetric: "ACTIVE",
+	floatingipsDownCountMetric:   "DOWN",
+	floatingipsErrorCountMetric:  "ERROR",
+}
+
+//ipsecSiteConnectionsStatusMetrics maps metrics which indicate number of IPsec site connections in given status to the status
+var ipsecSiteConnectionsStatusMetrics = map[string]string{
+	ipsecSiteConnectionsActiveCountMetric: "ACTIVE",
+	ipsecSiteConnectionsDownCountMetric:   "DOWN",
+	ipsecSiteConnectionsErrorCountMetric:  "ERROR",
+}
+
+//firewallGroupsStatusMetrics maps metrics which indicate number of firewall groups in given status to the status
+var firewallGroupsStatusMetrics = map[string]string{
+	firewallGroupsActiveCountMetric:   "ACTIVE",
+	firewallGroupsInactiveCountMetric: "INACTIVE",
+	firewallGroupsDownCountMetric:     "DOWN",
+	firewallGroupsErrorCountMetric:    "ERROR",
+	firewallGroupsPendingCountMetric:  "PENDING",
+}
+
+//qosRulesTypeMetrics maps metrics which indicate number of QoS rules of given type to the type
+var qosRulesTypeMetrics = map[string]string{
+	qosRulesBandwidthLimitCountMetric:   "bandwidth_limit",
+	qosRulesDSCPMarkingCountMetric:      "dscp_marking",
+	qosRulesMinimumBandwidthCountMetric: "minimum_bandwidth",
+}
+
+//rbacPoliciesObjectTypeMetrics maps metrics which indicate number of RBAC policies sharing given type of object to the type
+var rbacPoliciesObjectTypeMetrics = map[string]string{
+	rbacPoliciesNetworkCountMetric:       "network",
+	rbacPoliciesQoSPolicyCountMetric:     "qos_policy",
+	rbacPoliciesSecurityGroupCountMetric: "security_group",
+	rbacPoliciesAddressScopeCountMetric:  "address_scope",
+	rbacPoliciesSubnetPoolCountMetric:    "subnetpool",
+	rbacPoliciesAddressGroupCountMetric:  "address_group",
+}
+
+//rbacPoliciesActionMetrics maps metrics which indicate number of RBAC policies with given action to the action
+var rbacPoliciesActionMetrics = map[string]string{
+	rbacPoliciesSharedCountMetric:   "access_as_shared",
+	rbacPoliciesExternalCountMetric: "access_as_external",
+}
+
+//names of resource families which are retrieved separately, failure of one family does not affect remaining ones
+const (
+	networksFamily           = "networks"
+	subnetsFamily            = "subnets"
+	routersFamily            = "routers"
+	portsFamily              = "ports"
+	floatingipsFamily        = "floatingips"
+	securityGroupsFamily     = "security_groups"
+	securityGroupRulesFamily = "security_group_rules"
+	quotasFamily             = "quotas"
+	ipAvailabilityFamily     = "ip_availability"
+	agentsFamily             = "agents"
+	lbaasFamily              = "lbaas"
+	lbaasQuotasFamily        = "lbaas_quotas"
+	ikePoliciesFamily        = "ikepolicies"
+	ipsecPoliciesFamily      = "ipsecpolicies"
+	vpnServicesFamily        = "vpnservices"
+	ipsecSiteConnsFamily     = "ipsec_site_connections"
+	rbacPoliciesFamily       = "rbac_policies"
+	subnetPoolsFamily        = "subnetpools"
+	qosPoliciesFamily        = "qos_policies"
+	fwaasFamily              = "fwaas"
+)
+
+//resourceFamilies slice of names of resource families
+var resourceFamilies = []string{
+	networksFamily,
+	subnetsFamily,
+	routersFamily,
+	portsFamily,
+	floatingipsFamily,
+	securityGroupsFamily,
+	securityGroupRulesFamily,
+	quotasFamily,
+	ipAvailabilityFamily,
+	agentsFamily,
+	lbaasFamily,
+	lbaasQuotasFamily,
+	ikePoliciesFamily,
+	ipsecPoliciesFamily,
+	vpnServicesFamily,
+	ipsecSiteConnsFamily,
+	rbacPoliciesFamily,
+	subnetPoolsFamily,
+	qosPoliciesFamily,
+	fwaasFamily,
+}
+
+//subnetPoolMetrics slice of names of metrics which indicate address space of subnet pools
+var subnetPoolMetrics = []string{
+	prefixCapacityMetric,
+	prefixFreeMetric,
+	prefixUtilizationMetric,
+}
+
+//lbaasCollections slice of names of collections of load balancing resources which are counted by metrics
+var lbaasCollections = []string{
+	loadbalancers.LoadBalancers.Key,
+	loadbalancers.Listeners.Key,
+	loadbalancers.Pools.Key,
+	loadbalancers.MembersKey,
+	loadbalancers.HealthMonitors.Key,
+}
+
+//lbaasResourceNames maps collections of load balancing resources to names of resources used in descriptions of metrics
+var lbaasResourceNames = map[string]string{
+	loadbalancers.LoadBalancers.Key:  "load balancers",
+	loadbalancers.Listeners.Key:      "listeners",
+	loadbalancers.Pools.Key:          "pools",
+	loadbalancers.MembersKey:         "pool members",
+	loadbalancers.HealthMonitors.Key: "health monitors",
+}
+
+//lbaasMetric describes metric which indicates number of load balancing resources of collection, optionally only those in given status
+type lbaasMetric struct {
+	collection         string
+	provisioningStatus string
+	operatingStatus    string
+}
+
+//lbaasMetricNames slice of names of metrics which indicate number of load balancing resources, lbaasMetrics maps them to counted resources
+var lbaasMetricNames, lbaasMetrics = getLBaaSMetrics()
+
+//ipAvailabilityMetrics slice of names of metrics which indicate IP address availability of networks and subnets
+var ipAvailabilityMetrics = []string{
+	ipTotalMetric,
+	ipUsedMetric,
+	ipUtilizationMetric,
+}
+
+//countMetricResources maps metrics which indicate number of resources to Neutron resources
+var countMetricResources = map[string]tenantresources.Resource{
+	networksCountMetric:                   tenantresources.Networks,
+	subnetsCountMetric:                    tenantresources.Subnets,
+	routersCountMetric:                    tenantresources.Routers,
+	portsCountMetric:                      tenantresources.Ports,
+	portsActiveCountMetric:                tenantresources.Ports,
+	portsDownCountMetric:                  tenantresources.Ports,
+	portsBuildCountMetric:                 tenantresources.Ports,
+	portsErrorCountMetric:                 tenantresources.Ports,
+	portsComputeCountMetric:               tenantresources.Ports,
+	portsRouterInterfaceCountMetric:       tenantresources.Ports,
+	portsDHCPCountMetric:                  tenantresources.Ports,
+	portsFloatingIPCountMetric:            tenantresources.Ports,
+	portsUnboundCountMetric:               tenantresources.Ports,
+	portsOtherCountMetric:                 tenantresources.Ports,
+	portsQoSPolicyCountMetric:             tenantresources.Ports,
+	networksQoSPolicyCountMetric:          tenantresources.Networks,
+	floatingipsCountMetric:                tenantresources.FloatingIPs,
+	floatingipsAssociatedCountMetric:      tenantresources.FloatingIPs,
+	floatingipsUnassociatedCountMetric:    tenantresources.FloatingIPs,
+	floatingipsActiveCountMetric:          tenantresources.FloatingIPs,
+	floatingipsDownCountMetric:            tenantresources.FloatingIPs,
+	floatingipsErrorCountMetric:           tenantresources.FloatingIPs,
+	securityGroupsCountMetric:             tenantresources.SecurityGroups,
+	securityGroupRulesCountMetric:         tenantresources.SecurityGroupRules,
+	ikePoliciesCountMetric:                tenantresources.IKEPolicies,
+	ipsecPoliciesCountMetric:              tenantresources.IPsecPolicies,
+	vpnServicesCountMetric:                tenantresources.VPNServices,
+	ipsecSiteConnectionsCountMetric:       tenantresources.IPsecSiteConnections,
+	ipsecSiteConnectionsActiveCountMetric: tenantresources.IPsecSiteConnections,
+	ipsecSiteConnectionsDownCountMetric:   tenantresources.IPsecSiteConnections,
+	ipsecSiteConnectionsErrorCountMetric:  tenantresources.IPsecSiteConnections,
+	rbacPoliciesCountMetric:               tenantresources.RBACPolicies,
+	rbacPoliciesNetworkCountMetric:        tenantresources.RBACPolicies,
+	rbacPoliciesQoSPolicyCountMetric:      tenantresources.RBACPolicies,
+	rbacPoliciesSecurityGroupCountMetric:  tenantresources.RBACPolicies,
+	rbacPoliciesAddressScopeCountMetric:   tenantresources.RBACPolicies,
+	rbacPoliciesSubnetPoolCountMetric:     tenantresources.RBACPolicies,
+	rbacPoliciesAddressGroupCountMetric:   tenantresources.RBACPolicies,
+	rbacPoliciesSharedCountMetric:         tenantresources.RBACPolicies,
+	rbacPoliciesExternalCountMetric:       tenantresources.RBACPolicies,
+	subnetPoolsCountMetric:                tenantresources.SubnetPools,
+	qosPoliciesCountMetric:                tenantresources.QoSPolicies,
+	qosRulesCountMetric:                   tenantresources.QoSPolicies,
+	qosRulesBandwidthLimitCountMetric:     tenantresources.QoSPolicies,
+	qosRulesDSCPMarkingCountMetric:        tenantresources.QoSPolicies,
+	qosRulesMinimumBandwidthCountMetric:   tenantresources.QoSPolicies,
+	firewallGroupsCountMetric:             tenantresources.FirewallGroups,
+	firewallGroupsActiveCountMetric:       tenantresources.FirewallGroups,
+	firewallGroupsInactiveCountMetric:     tenantresources.FirewallGroups,
+	firewallGroupsDownCountMetric:         tenantresources.FirewallGroups,
+	firewallGroupsErrorCountMetric:        tenantresources.FirewallGroups,
+	firewallGroupsPendingCountMetric:      tenantresources.FirewallGroups,
+	firewallGroupPortsCountMetric:         tenantresources.FirewallGroups,
+	firewallPoliciesCountMetric:           tenantresources.FirewallPolicies,
+	firewallRulesCountMetric:              tenantresources.FirewallRules,
+}
+
+//collectionFamilies maps collections to names of resource families which differ from keys of collections
+var collectionFamilies = map[tenantresources.Resource]string{
+	tenantresources.QoSPolicies:      qosPoliciesFamily,
+	tenantresources.FirewallGroups:   fwaasFamily,
+	tenantresources.FirewallPolicies: fwaasFamily,
+	tenantresources.FirewallRules:    fwaasFamily,
+}
+
+//familyExtensions maps resource families provided by optional Neutron extensions to aliases of these extensions
+//Families whose extension is not loaded are skipped without being regarded as failed
+var familyExtensions = map[string]string{
+	fwaasFamily:          openstackintel.FWaaSExtensionAlias,
+	ikePoliciesFamily:    openstackintel.VPNaaSExtensionAlias,
+	ipsecPoliciesFamily:  openstackintel.VPNaaSExtensionAlias,
+	vpnServicesFamily:    openstackintel.VPNaaSExtensionAlias,
+	ipsecSiteConnsFamily: openstackintel.VPNaaSExtensionAlias,
+	qosPoliciesFamily:    openstackintel.QoSExtensionAlias,
+	lbaasFamily:          openstackintel.LBaaSExtensionAlias,
+}
+
+//neutronQuotas slice of names of quotas which are exposed as metrics
+var neutronQuotas = []string{
+	"firewall_group",
+	"firewall_policy",
+	"firewall_rule",
+	"floatingip",
+	"healthmonitor",
+	"ikepolicy",
+	"ipsec_site_connection",
+	"ipsecpolicy",
+	"listener",
+	"loadbalancer",
+	"member",
+	"network",
+	"pool",
+	"port",
+	"rbac_policy",
+	"router",
+	"security_group",
+	"security_group_rule",
+	"subnet",
+	"subnetpool",
+	"vpnservice",
+}
+
+//ipAvailabilitySourceFamilies resource families which are used to calculate IP availability if network IP availability extension is not available
+var ipAvailabilitySourceFamilies = []string{networksFamily, subnetsFamily, portsFamily}
+
+//lbaasQuotas names of quotas of load balancing resources, they are retrieved from Octavia if it is used instead of Neutron LBaaS v2
+var lbaasQuotas = map[string]bool{
+	"loadbalancer":  true,
+	"listener":      true,
+	"pool":          true,
+	"member":        true,
+	"healthmonitor": true,
+}
+
+//quotaUsageMetrics maps quota names to metrics which indicate usage of limited resource
+var quotaUsageMetrics = map[string]string{
+	"network":               networksCountMetric,
+	"subnet":                subnetsCountMetric,
+	"router":                routersCountMetric,
+	"port":                  portsCountMetric,
+	"floatingip":            floatingipsCountMetric,
+	"security_group":        securityGroupsCountMetric,
+	"security_group_rule":   securityGroupRulesCountMetric,
+	"loadbalancer":          loadbalancers.LoadBalancers.Key + countSuffix,
+	"listener":              loadbalancers.Listeners.Key + countSuffix,
+	"pool":                  loadbalancers.Pools.Key + countSuffix,
+	"member":                loadbalancers.MembersKey + countSuffix,
+	"healthmonitor":         loadbalancers.HealthMonitors.Key + countSuffix,
+	"ikepolicy":             ikePoliciesCountMetric,
+	"ipsecpolicy":           ipsecPoliciesCountMetric,
+	"vpnservice":            vpnServicesCountMetric,
+	"ipsec_site_connection": ipsecSiteConnectionsCountMetric,
+	"rbac_policy":           rbacPoliciesCountMetric,
+	"subnetpool":            subnetPoolsCountMetric,
+	"firewall_group":        firewallGroupsCountMetric,
+	"firewall_policy":       firewallPoliciesCountMetric,
+	"firewall_rule":         firewallRulesCountMetric,
 }
 
 //neutronInfoFields contains information (description and unit) about metrics
@@ -123,371 +816,2212 @@ var neutronInfoFields = map[string]infoFields{
 		description: "number of tenant ports",
 		unit:        "",
 	},
+	portsActiveCountMetric: infoFields{
+		description: "number of tenant ports in ACTIVE status",
+		unit:        "",
+	},
+	portsDownCountMetric: infoFields{
+		description: "number of tenant ports in DOWN status",
+		unit:        "",
+	},
+	portsBuildCountMetric: infoFields{
+		description: "number of tenant ports in BUILD status",
+		unit:        "",
+	},
+	portsErrorCountMetric: infoFields{
+		description: "number of tenant ports in ERROR status",
+		unit:        "",
+	},
+	portsComputeCountMetric: infoFields{
+		description: "number of tenant ports of instances (device owner compute:*)",
+		unit:        "",
+	},
+	portsRouterInterfaceCountMetric: infoFields{
+		description: "number of tenant router interface ports",
+		unit:        "",
+	},
+	portsDHCPCountMetric: infoFields{
+		description: "number of tenant DHCP ports",
+		unit:        "",
+	},
+	portsFloatingIPCountMetric: infoFields{
+		description: "number of tenant floating IP ports",
+		unit:        "",
+	},
+	portsQoSPolicyCountMetric: infoFields{
+		description: "number of tenant ports with QoS policy attached",
+		unit:        "",
+	},
+	networksQoSPolicyCountMetric: infoFields{
+		description: "number of tenant networks with QoS policy attached",
+		unit:        "",
+	},
+	qosPoliciesCountMetric: infoFields{
+		description: "number of tenant QoS policies",
+		unit:        "",
+	},
+	qosRulesCountMetric: infoFields{
+		description: "number of rules of tenant QoS policies",
+		unit:        "",
+	},
+	qosRulesBandwidthLimitCountMetric: infoFields{
+		description: "number of bandwidth_limit rules of tenant QoS policies",
+		unit:        "",
+	},
+	qosRulesDSCPMarkingCountMetric: infoFields{
+		description: "number of dscp_marking rules of tenant QoS policies",
+		unit:        "",
+	},
+	qosRulesMinimumBandwidthCountMetric: infoFields{
+		description: "number of minimum_bandwidth rules of tenant QoS policies",
+		unit:        "",
+	},
+	portsUnboundCountMetric: infoFields{
+		description: "number of tenant ports without device owner",
+		unit:        "",
+	},
+	portsOtherCountMetric: infoFields{
+		description: "number of tenant ports with other device owner (e.g. router gateways)",
+		unit:        "",
+	},
 	floatingipsCountMetric: infoFields{
 		description: "number of tenant floating IPs",
 		unit:        "",
 	},
-	quotas + "floatingip": infoFields{
-		description: "number of floating IP addresses allowed for a tenant ( -1 means no limit)",
+	floatingipsAssociatedCountMetric: infoFields{
+		description: "number of tenant floating IPs associated with port",
 		unit:        "",
 	},
-	quotas + "ikepolicy": infoFields{
-		description: "number of IKE policies allowed for a tenant",
+	floatingipsUnassociatedCountMetric: infoFields{
+		description: "number of tenant floating IPs allocated but not associated with any port",
 		unit:        "",
 	},
-	quotas + "ipsec_site_connection": infoFields{
-		description: "number of IPSec connections allowed for a tenant",
+	floatingipsActiveCountMetric: infoFields{
+		description: "number of tenant floating IPs in ACTIVE status",
 		unit:        "",
 	},
-	quotas + "ipsecpolicy": infoFields{
-		description: "number of IPSec policies allowed for a tenant",
+	floatingipsDownCountMetric: infoFields{
+		description: "number of tenant floating IPs in DOWN status",
 		unit:        "",
 	},
-	quotas + "network": infoFields{
-		description: "number of networks allowed for a tenant",
+	floatingipsErrorCountMetric: infoFields{
+		description: "number of tenant floating IPs in ERROR status",
 		unit:        "",
 	},
-	quotas + "port": infoFields{
-		description: "number of ports allowed for a tenant",
+	securityGroupsCountMetric: infoFields{
+		description: "number of tenant security groups",
 		unit:        "",
 	},
-	quotas + "rbac_policy": infoFields{
-		description: "number of role-based access control (RBAC) policies for a tenant",
+	securityGroupRulesCountMetric: infoFields{
+		description: "number of tenant security group rules",
 		unit:        "",
 	},
-	quotas + "router": infoFields{
-		description: "number of routers allowed for a tenant",
+	rbacPoliciesCountMetric: infoFields{
+		description: "number of tenant RBAC policies",
 		unit:        "",
 	},
-	quotas + "security_group": infoFields{
-		description: "number of security groups allowed for a tenant",
+	rbacPoliciesNetworkCountMetric: infoFields{
+		description: "number of tenant RBAC policies which share networks",
 		unit:        "",
 	},
-	quotas + "security_group_rule": infoFields{
-		description: "number of security group rules allowed for a tenant",
+	rbacPoliciesQoSPolicyCountMetric: infoFields{
+		description: "number of tenant RBAC policies which share QoS policies",
 		unit:        "",
 	},
-	quotas + "subnet": infoFields{
-		description: "number of subnets allowed for a tenant",
+	rbacPoliciesSecurityGroupCountMetric: infoFields{
+		description: "number of tenant RBAC policies which share security groups",
 		unit:        "",
 	},
-	quotas + "subnetpool": infoFields{
-		description: "number of subnet pools allowed for a tenant",
+	rbacPoliciesAddressScopeCountMetric: infoFields{
+		description: "number of tenant RBAC policies which share address scopes",
 		unit:        "",
 	},
-}
-
-//Collector neutron plugin struct
-type Collector struct {
-	provider *gophercloud.ProviderClient
-}
-
-//Meta returns meta data for plugin
-func Meta() *plugin.PluginMeta {
-	return plugin.NewPluginMeta(
-		pluginName,
-		version,
-		pluginType,
-		[]string{plugin.SnapGOBContentType},
-		[]string{plugin.SnapGOBContentType},
-		plugin.RoutingStrategy(plugin.StickyRouting),
-	)
-}
-
-// New creates initialized instance of Glance collector
-func New() *Collector {
-	return &Collector{}
-}
-
-// GetMetricTypes returns list of available metric types
-// It returns error in case retrieval was not successful
-func (c *Collector) GetMetricTypes(cfg plugin.ConfigType) ([]plugin.MetricType, error) {
-	mts := []plugin.MetricType{}
-	items, err := config.GetConfigItems(cfg, cfgURL, cfgUser, cfgPassword, cfgTenant)
-	if err != nil {
-		return nil, err
-	}
-	domain_name := ""
-	domain_id := ""
-
-	endpoint := items[cfgURL].(string)
-	user := items[cfgUser].(string)
-	password := items[cfgPassword].(string)
-	tenant := items[cfgTenant].(string)
-	dom_name, _ := config.GetConfigItem(cfg, "domain_name")
-	dom_id, _ := config.GetConfigItem(cfg, "domain_id")
-	if dom_name != nil {
-		domain_name = dom_name.(string)
-	}
-	if dom_id != nil {
-		domain_id = dom_id.(string)
-	}
-	if c.provider == nil {
-		provider, serr := openstackintel.Authenticate(endpoint, user, password, tenant, domain_name, domain_id)
-		if serr != nil {
-			log.WithFields(serr.Fields()).Warn(serr.Error())
-			return nil, serr
-		}
-		c.provider = provider
-	}
-
-	// Retrieve list of all available tenants for provided endpoint, user and password
-
-	identityClient := openstackgophercloud.NewIdentityV2(c.provider)
-	allTenants, serr := openstackintel.GetAllTenants(identityClient)
-	if serr != nil {
-		log.WithFields(serr.Fields()).Warn(serr.Error())
+	rbacPoliciesSubnetPoolCountMetric: infoFields{
+		description: "number of tenant RBAC policies which share subnet pools",
+		unit:        "",
+	},
+	rbacPoliciesAddressGroupCountMetric: infoFields{
+		description: "number of tenant RBAC policies which share address groups",
+		unit:        "",
+	},
+	rbacPoliciesSharedCountMetric: infoFields{
+		description: "number of tenant RBAC policies with access_as_shared action",
+		unit:        "",
+	},
+	rbacPoliciesExternalCountMetric: infoFields{
+		description: "number of tenant RBAC policies with access_as_external action",
+		unit:        "",
+	},
+	subnetPoolsCountMetric: infoFields{
+		description: "number of tenant subnet pools",
+		unit:        "",
+	},
+	prefixCapacityMetric: infoFields{
+		description: "number of addresses of subnet pool prefixes",
+		unit:        "",
+	},
+	prefixFreeMetric: infoFields{
+		description: "number of addresses of subnet pool which are not allocated to subnets",
+		unit:        "",
+	},
+	prefixUtilizationMetric: infoFields{
+		description: "percentage of addresses of subnet pool allocated to subnets",
+		unit:        "percent",
+	},
+	firewallGroupsCountMetric: infoFields{
+		description: "number of tenant firewall groups",
+		unit:        "",
+	},
+	firewallGroupsActiveCountMetric: infoFields{
+		description: "number of tenant firewall groups in ACTIVE status",
+		unit:        "",
+	},
+	firewallGroupsInactiveCountMetric: infoFields{
+		description: "number of tenant firewall groups in INACTIVE status",
+		unit:        "",
+	},
+	firewallGroupsDownCountMetric: infoFields{
+		description: "number of tenant firewall groups in DOWN status",
+		unit:        "",
+	},
+	firewallGroupsErrorCountMetric: infoFields{
+		description: "number of tenant firewall groups in ERROR status",
+		unit:        "",
+	},
+	firewallGroupsPendingCountMetric: infoFields{
+		description: "number of tenant firewall groups in PENDING_CREATE, PENDING_UPDATE or PENDING_DELETE status",
+		unit:        "",
+	},
+	firewallGroupPortsCountMetric: infoFields{
+		description: "number of router ports bound to tenant firewall groups",
+		unit:        "",
+	},
+	firewallPoliciesCountMetric: infoFields{
+		description: "number of tenant firewall policies",
+		unit:        "",
+	},
+	firewallRulesCountMetric: infoFields{
+		description: "number of tenant firewall rules",
+		unit:        "",
+	},
+	ikePoliciesCountMetric: infoFields{
+		description: "number of tenant VPN IKE policies",
+		unit:        "",
+	},
+	ipsecPoliciesCountMetric: infoFields{
+		description: "number of tenant VPN IPsec policies",
+		unit:        "",
+	},
+	vpnServicesCountMetric: infoFields{
+		description: "number of tenant VPN services",
+		unit:        "",
+	},
+	ipsecSiteConnectionsCountMetric: infoFields{
+		description: "number of tenant IPsec site connections",
+		unit:        "",
+	},
+	ipsecSiteConnectionsActiveCountMetric: infoFields{
+		description: "number of tenant IPsec site connections in ACTIVE status",
+		unit:        "",
+	},
+	ipsecSiteConnectionsDownCountMetric: infoFields{
+		description: "number of tenant IPsec site connections in DOWN status",
+		unit:        "",
+	},
+	ipsecSiteConnectionsErrorCountMetric: infoFields{
+		description: "number of tenant IPsec site connections in ERROR status",
+		unit:        "",
+	},
+	ipTotalMetric: infoFields{
+		description: "number of allocatable IP addresses",
+		unit:        "",
+	},
+	ipUsedMetric: infoFields{
+		description: "number of used IP addresses",
+		unit:        "",
+	},
+	ipUtilizationMetric: infoFields{
+		description: "percentage of used IP addresses",
+		unit:        "percent",
+	},
+	quotas + "firewall_group": infoFields{
+		description: "number of firewall groups allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "firewall_policy": infoFields{
+		description: "number of firewall policies allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "firewall_rule": infoFields{
+		description: "number of firewall rules allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "floatingip": infoFields{
+		description: "number of floating IP addresses allowed for a tenant ( -1 means no limit)",
+		unit:        "",
+	},
+	quotas + "healthmonitor": infoFields{
+		description: "number of load balancer health monitors allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "ikepolicy": infoFields{
+		description: "number of IKE policies allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "ipsec_site_connection": infoFields{
+		description: "number of IPSec connections allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "ipsecpolicy": infoFields{
+		description: "number of IPSec policies allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "listener": infoFields{
+		description: "number of load balancer listeners allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "loadbalancer": infoFields{
+		description: "number of load balancers allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "member": infoFields{
+		description: "number of load balancer pool members allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "network": infoFields{
+		description: "number of networks allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "pool": infoFields{
+		description: "number of load balancer pools allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "port": infoFields{
+		description: "number of ports allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "rbac_policy": infoFields{
+		description: "number of role-based access control (RBAC) policies for a tenant",
+		unit:        "",
+	},
+	quotas + "router": infoFields{
+		description: "number of routers allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "security_group": infoFields{
+		description: "number of security groups allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "security_group_rule": infoFields{
+		description: "number of security group rules allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "subnet": infoFields{
+		description: "number of subnets allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "subnetpool": infoFields{
+		description: "number of subnet pools allowed for a tenant",
+		unit:        "",
+	},
+	quotas + "vpnservice": infoFields{
+		description: "number of VPN services allowed for a tenant",
+		unit:        "",
+	},
+}
+
+//Collector neutron plugin struct
+type Collector struct {
+	provider *gophercloud.ProviderClient
+}
+
+//Meta returns meta data for plugin
+func Meta() *plugin.PluginMeta {
+	return plugin.NewPluginMeta(
+		pluginName,
+		version,
+		pluginType,
+		[]string{plugin.SnapGOBContentType},
+		[]string{plugin.SnapGOBContentType},
+		plugin.RoutingStrategy(plugin.StickyRouting),
+	)
+}
+
+// New creates initialized instance of Glance collector
+func New() *Collector {
+	return &Collector{}
+}
+
+// GetMetricTypes returns list of available metric types
+// It returns error in case retrieval was not successful
+func (c *Collector) GetMetricTypes(cfg plugin.ConfigType) ([]plugin.MetricType, error) {
+	mts := []plugin.MetricType{}
+	// only credentials are verified, no tenants nor quotas are retrieved when plugin is loaded
+	if err := c.authenticate(cfg); err != nil {
+		return nil, err
+	}
+
+	for _, family := range resourceFamilies {
+		mts = append(mts, plugin.MetricType{
+			Namespace_:   core.NewNamespace(vendor, openstack, pluginName, errorsNSPart, family),
+			Config_:      cfg.ConfigDataNode,
+			Description_: fmt.Sprintf("indicates whether collection of %s failed (1) or succeeded (0)", family),
+		})
+	}
+
+	for _, metricName := range getCountMetrics() {
+		info := getInfoFields(metricName)
+		mts = append(mts, plugin.MetricType{
+			Namespace_:   core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, metricName),
+			Config_:      cfg.ConfigDataNode,
+			Description_: fmt.Sprintf("number of %s in the whole cloud, including resources of unknown tenants", strings.TrimPrefix(info.description, "number of tenant ")),
+			Unit_:        info.unit,
+		})
+		mts = append(mts, plugin.MetricType{
+			Namespace_:   core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, orphaned+metricName),
+			Config_:      cfg.ConfigDataNode,
+			Description_: fmt.Sprintf("number of %s owned by tenants which do not exist in Keystone", strings.TrimPrefix(info.description, "number of tenant ")),
+			Unit_:        info.unit,
+		})
+	}
+
+	// agents are not owned by tenants, agent type and host are dynamic elements resolved at collect time
+	addAgentMetric := func(namespace core.Namespace, description, unit string) {
+		mts = append(mts, plugin.MetricType{
+			Namespace_:   namespace,
+			Config_:      cfg.ConfigDataNode,
+			Description_: description,
+			Unit_:        unit,
+		})
+	}
+	agentNamespace := func(metricName string) core.Namespace {
+		return core.NewNamespace(vendor, openstack, pluginName, agentsNSPart).AddDynamicElement(agentTypeElement, "type of agent (e.g. l3_agent, dhcp_agent)").
+			AddDynamicElement(hostElement, "host of agent").AddStaticElement(metricName)
+	}
+	agentTypeNamespace := func(metricName string) core.Namespace {
+		return core.NewNamespace(vendor, openstack, pluginName, agentsNSPart).AddDynamicElement(agentTypeElement, "type of agent (e.g. l3_agent, dhcp_agent)").
+			AddStaticElement(metricName)
+	}
+	addAgentMetric(agentNamespace(agentAliveMetric), "indicates whether agent is alive (1) or down (0)", "")
+	addAgentMetric(agentNamespace(agentAdminStateUpMetric), "indicates whether agent is administratively enabled (1) or disabled (0)", "")
+	addAgentMetric(agentNamespace(agentHeartbeatAgeMetric), "number of seconds since the last heartbeat of agent", "s")
+	addAgentMetric(agentTypeNamespace(agentsAliveCountMetric), "number of alive agents of given type", "")
+	addAgentMetric(agentTypeNamespace(agentsDownCountMetric), "number of down agents of given type", "")
+
+	tenantNamespace, err := getTenantNamespace(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// tenants are filtered at collect time, filters are only validated here
+	if _, err := getTenantFilter(cfg); err != nil {
+		return nil, err
+	}
+	tenantElement, tenantElementDescription := tenantNameElement, "name of tenant"
+	if tenantNamespace == tenantNamespaceID {
+		tenantElement, tenantElementDescription = tenantIDElement, "ID of tenant"
+	}
+
+	// Tenants are not enumerated here, tenant is a dynamic element resolved at collect time
+	addMetric := func(metricName, description, unit string) {
+		mts = append(mts, plugin.MetricType{
+			Namespace_:   core.NewNamespace(vendor, openstack, pluginName).AddDynamicElement(tenantElement, tenantElementDescription).AddStaticElement(metricName),
+			Config_:      cfg.ConfigDataNode,
+			Description_: description,
+			Unit_:        unit,
+		})
+	}
+
+	for _, k := range neutronQuotas {
+		info := getInfoFields(quotas + k)
+		addMetric(quotas+k, info.description, info.unit)
+		addMetric(quotas+k+quotaUsedSuffix, fmt.Sprintf("number of %s resources used by a tenant, as reported by quota details", k), "")
+		addMetric(quotas+k+quotaReservedSuffix, fmt.Sprintf("number of %s resources reserved for a tenant, as reported by quota details", k), "")
+
+		if _, ok := quotaUsageMetrics[k]; !ok {
+			continue
+		}
+		addMetric(utilization+k, fmt.Sprintf("ratio of used to allowed %s resources for a tenant ( -1 means no limit)", k), "")
+		addMetric(headroom+k, fmt.Sprintf("number of %s resources which still can be created by a tenant ( -1 means no limit)", k), "")
+	}
+
+	for _, metricName := range getCountMetrics() {
+		info := getInfoFields(metricName)
+		addMetric(metricName, info.description, info.unit)
+	}
+
+	for _, metricName := range ipAvailabilityMetrics {
+		info := getInfoFields(metricName)
+		mts = append(mts, plugin.MetricType{
+			Namespace_: core.NewNamespace(vendor, openstack, pluginName).AddDynamicElement(tenantElement, tenantElementDescription).
+				AddStaticElement(networkNSPart).AddDynamicElement(networkIDElement, "ID of network").AddStaticElement(metricName),
+			Config_:      cfg.ConfigDataNode,
+			Description_: info.description + " of network",
+			Unit_:        info.unit,
+		})
+		mts = append(mts, plugin.MetricType{
+			Namespace_: core.NewNamespace(vendor, openstack, pluginName).AddDynamicElement(tenantElement, tenantElementDescription).
+				AddStaticElement(networkNSPart).AddDynamicElement(networkIDElement, "ID of network").
+				AddStaticElement(subnetsNSPart).AddDynamicElement(subnetIDElement, "ID of subnet").AddStaticElement(metricName),
+			Config_:      cfg.ConfigDataNode,
+			Description_: info.description + " of subnet",
+			Unit_:        info.unit,
+		})
+	}
+
+	// metrics of single network or router, network (router) is a dynamic element resolved at collect time too
+	addResourceMetric := func(collection, idElement, idDescription, metricName, description string) {
+		mts = append(mts, plugin.MetricType{
+			Namespace_: core.NewNamespace(vendor, openstack, pluginName).AddDynamicElement(tenantElement, tenantElementDescription).
+				AddStaticElement(collection).AddDynamicElement(idElement, idDescription).AddStaticElement(metricName),
+			Config_:      cfg.ConfigDataNode,
+			Description_: description,
+		})
+	}
+	addResourceMetric(networkNSPart, networkIDElement, "ID of network", portsCountMetric, "number of ports of network, including ports of other tenants if network is shared")
+	addResourceMetric(networkNSPart, networkIDElement, "ID of network", subnetsCountMetric, "number of subnets of network")
+	addResourceMetric(routerNSPart, routerIDElement, "ID of router", interfacesCountMetric, "number of interfaces of router")
+
+	for _, metricName := range subnetPoolMetrics {
+		info := getInfoFields(metricName)
+		mts = append(mts, plugin.MetricType{
+			Namespace_: core.NewNamespace(vendor, openstack, pluginName).AddDynamicElement(tenantElement, tenantElementDescription).
+				AddStaticElement(subnetPoolsNSPart).AddDynamicElement(subnetPoolIDElement, "ID of subnet pool").AddStaticElement(metricName),
+			Config_:      cfg.ConfigDataNode,
+			Description_: info.description,
+			Unit_:        info.unit,
+		})
+	}
+	return mts, nil
+}
+
+// CollectMetrics returns list of requested metric values
+// It returns error in case retrieval was not successful
+func (c *Collector) CollectMetrics(metricTypes []plugin.MetricType) ([]plugin.MetricType, error) {
+	if err := c.authenticate(metricTypes[0]); err != nil {
+		return nil, err
+	}
+
+	identityClient, serr := openstackintel.NewIdentityClient(c.provider)
+	if serr != nil {
+		log.WithFields(serr.Fields()).Warn(serr.Error())
+		return nil, serr
+	}
+
+	tenantList, serr := openstackintel.GetAllTenants(identityClient)
+	if serr != nil {
+		log.WithFields(serr.Fields()).Warn(serr.Error())
 		return nil, serr
 	}
 
-	networkClient, err := openstackgophercloud.NewNetworkV2(c.provider, gophercloud.EndpointOpts{})
-	if err != nil {
-		return nil, err
+	networkClient, err := openstackgophercloud.NewNetworkV2(c.provider, gophercloud.EndpointOpts{})
+	if err != nil {
+		return nil, err
+	}
+
+	tenantNamespace, err := getTenantNamespace(metricTypes[0])
+	if err != nil {
+		return nil, err
+	}
+	filter, err := getTenantFilter(metricTypes[0])
+	if err != nil {
+		return nil, err
+	}
+	// filtered out tenants are not resolved and no per-tenant requests are sent for them, but they are not regarded as orphaned owners
+	allTenants := tenantList
+	tenantList = skipReservedTenants(filter.apply(tenantList), tenantNamespace)
+
+	listOpts := getListOpts(metricTypes[0])
+	maxConcurrent := getMaxConcurrentRequests(metricTypes[0])
+
+	// only resource families and tenants needed by requested metrics are retrieved
+	requestedFamilies, requestedTenants := getDemand(metricTypes)
+	quotaTenantList := filterTenants(tenantList, requestedTenants, tenantNamespace)
+
+	var done sync.WaitGroup
+	var failuresMutex sync.Mutex
+	failures := map[string]serror.SnapError{}
+
+	// load balancing resources are provided by Octavia instead of Neutron extension if it is present in service catalog
+	octaviaClient := openstackintel.NewOctaviaClient(c.provider)
+	extensions := map[string]string{}
+	for family, alias := range familyExtensions {
+		if family == lbaasFamily && octaviaClient != nil {
+			continue
+		}
+		extensions[family] = alias
+	}
+
+	// families of optional extensions which are not loaded are neither retrieved nor regarded as failed
+	unavailable, extensionFailures := discoverExtensions(networkClient, requestedFamilies, extensions)
+	for family, serr := range extensionFailures {
+		failures[family] = serr
+	}
+
+	// IP availability is calculated from networks, subnets and ports if Neutron does not provide network IP availability extension,
+	// these families are then retrieved only once, even if they are requested by other metrics too
+	var ipAvailabilities []ipavailability.NetworkIPAvailability
+	calculateIPAvailability := false
+	if requestedFamilies[ipAvailabilityFamily] {
+		var serr serror.SnapError
+		ipAvailabilities, serr = openstackintel.GetNetworkIPAvailabilities(networkClient)
+		switch {
+		case serr != nil:
+			serr.SetFields(mergeFields(serr.Fields(), map[string]interface{}{"resourceFamily": ipAvailabilityFamily}))
+			log.WithFields(serr.Fields()).Warn(serr.Error())
+			failures[ipAvailabilityFamily] = serr
+		case ipAvailabilities == nil:
+			log.WithFields(log.Fields{"resourceFamily": ipAvailabilityFamily}).Debug("Network IP availability extension is not available, IP availability is calculated from networks, subnets and ports")
+			calculateIPAvailability = true
+			for _, family := range ipAvailabilitySourceFamilies {
+				requestedFamilies[family] = true
+			}
+		}
+	}
+
+	// fetch retrieves requested resource family in separate goroutine, failure is recorded instead of aborting collection
+	fetch := func(family string, f func() serror.SnapError) {
+		if !requestedFamilies[family] || unavailable[family] || extensionFailures[family] != nil {
+			return
+		}
+
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			serr := f()
+			if serr == nil {
+				return
+			}
+			serr.SetFields(mergeFields(serr.Fields(), map[string]interface{}{"resourceFamily": family}))
+			log.WithFields(serr.Fields()).Warn(serr.Error())
+
+			failuresMutex.Lock()
+			failures[family] = serr
+			failuresMutex.Unlock()
+		}()
+	}
+
+	// resources are counted for all tenants which exist in Keystone, so that only resources of tenants which do not exist are kept as unlisted
+	var tenantNetworks openstackintel.NetworkCounts
+	fetch(networksFamily, func() (serr serror.SnapError) {
+		tenantNetworks, serr = openstackintel.GetNetworkDetailsPerTenant(networkClient, allTenants, listOpts)
+		return serr
+	})
+
+	var tenantSubnets openstackintel.SubnetCounts
+	fetch(subnetsFamily, func() (serr serror.SnapError) {
+		tenantSubnets, serr = openstackintel.GetSubnetDetailsPerTenant(networkClient, allTenants, listOpts)
+		return serr
+	})
+
+	var tenantRouters openstackintel.RouterCounts
+	fetch(routersFamily, func() (serr serror.SnapError) {
+		tenantRouters, serr = openstackintel.GetRouterDetailsPerTenant(networkClient, allTenants, listOpts)
+		return serr
+	})
+
+	var tenantPorts openstackintel.PortCounts
+	fetch(portsFamily, func() (serr serror.SnapError) {
+		tenantPorts, serr = openstackintel.GetPortsBreakdownPerTenant(networkClient, allTenants, listOpts)
+		return serr
+	})
+
+	var tenantFloatingips openstackintel.FloatingIPCounts
+	fetch(floatingipsFamily, func() (serr serror.SnapError) {
+		tenantFloatingips, serr = openstackintel.GetFloatingIPsBreakdownPerTenant(networkClient, allTenants, listOpts)
+		return serr
+	})
+
+	var tenantSecurityGroups openstackintel.TenantCounts
+	fetch(securityGroupsFamily, func() (serr serror.SnapError) {
+		tenantSecurityGroups, serr = openstackintel.GetSecurityGroupsCountPerTenant(networkClient, allTenants, listOpts)
+		return serr
+	})
+
+	var tenantSecurityGroupRules openstackintel.TenantCounts
+	fetch(securityGroupRulesFamily, func() (serr serror.SnapError) {
+		tenantSecurityGroupRules, serr = openstackintel.GetSecurityGroupRulesCountPerTenant(networkClient, allTenants, listOpts)
+		return serr
+	})
+
+	var tenantRBACPolicies openstackintel.RBACPolicyCounts
+	fetch(rbacPoliciesFamily, func() (serr serror.SnapError) {
+		tenantRBACPolicies, serr = openstackintel.GetRBACPoliciesCountPerTenant(networkClient, allTenants, listOpts)
+		return serr
+	})
+
+	var tenantSubnetPools openstackintel.SubnetPoolCounts
+	fetch(subnetPoolsFamily, func() (serr serror.SnapError) {
+		tenantSubnetPools, serr = openstackintel.GetSubnetPoolsCountPerTenant(networkClient, allTenants, listOpts)
+		return serr
+	})
+
+	var tenantQoSPolicies openstackintel.QoSPolicyCounts
+	fetch(qosPoliciesFamily, func() (serr serror.SnapError) {
+		tenantQoSPolicies, serr = openstackintel.GetQoSPoliciesCountPerTenant(networkClient, allTenants, listOpts)
+		return serr
+	})
+
+	var tenantFirewallGroups openstackintel.FirewallGroupCounts
+	var tenantFirewallPolicies, tenantFirewallRules openstackintel.TenantCounts
+	fetch(fwaasFamily, func() (serr serror.SnapError) {
+		if tenantFirewallGroups, serr = openstackintel.GetFirewallGroupsBreakdownPerTenant(networkClient, allTenants, listOpts); serr != nil {
+			return serr
+		}
+		if tenantFirewallPolicies, serr = openstackintel.GetFirewallPoliciesCountPerTenant(networkClient, allTenants, listOpts); serr != nil {
+			return serr
+		}
+		tenantFirewallRules, serr = openstackintel.GetFirewallRulesCountPerTenant(networkClient, allTenants, listOpts)
+		return serr
+	})
+
+	var tenantIKEPolicies openstackintel.TenantCounts
+	fetch(ikePoliciesFamily, func() (serr serror.SnapError) {
+		tenantIKEPolicies, serr = openstackintel.GetIKEPoliciesCountPerTenant(networkClient, allTenants, listOpts)
+		return serr
+	})
+
+	var tenantIPsecPolicies openstackintel.TenantCounts
+	fetch(ipsecPoliciesFamily, func() (serr serror.SnapError) {
+		tenantIPsecPolicies, serr = openstackintel.GetIPsecPoliciesCountPerTenant(networkClient, allTenants, listOpts)
+		return serr
+	})
+
+	var tenantVPNServices openstackintel.TenantCounts
+	fetch(vpnServicesFamily, func() (serr serror.SnapError) {
+		tenantVPNServices, serr = openstackintel.GetVPNServicesCountPerTenant(networkClient, allTenants, listOpts)
+		return serr
+	})
+
+	var tenantIPsecSiteConnections openstackintel.IPsecSiteConnectionCounts
+	fetch(ipsecSiteConnsFamily, func() (serr serror.SnapError) {
+		tenantIPsecSiteConnections, serr = openstackintel.GetIPsecSiteConnectionsBreakdownPerTenant(networkClient, allTenants, listOpts)
+		return serr
+	})
+
+	var agentList []agents.Agent
+	fetch(agentsFamily, func() (serr serror.SnapError) {
+		agentList, serr = openstackintel.GetAgents(networkClient)
+		return serr
+	})
+
+	var tenantLoadBalancers openstackintel.LoadBalancerCounts
+	fetch(lbaasFamily, func() (serr serror.SnapError) {
+		loadBalancerClient := octaviaClient
+		if loadBalancerClient == nil {
+			loadBalancerClient = networkClient
+		}
+		var poolErrors map[string]serror.SnapError
+		tenantLoadBalancers, poolErrors, serr = openstackintel.GetLoadBalancersBreakdownPerTenant(loadBalancerClient, allTenants, listOpts, maxConcurrent)
+		logTenantErrors(poolErrors)
+		return serr
+	})
+
+	var tenantQuotasList map[string]map[string]int64
+	var tenantQuotaDetails map[string]map[string]tenantquotas.QuotaDetails
+	fetch(quotasFamily, func() serror.SnapError {
+		var tenantErrors map[string]serror.SnapError
+		tenantQuotaDetails, tenantErrors = openstackintel.GetQuotaDetailsPerTenant(networkClient, quotaTenantList, maxConcurrent)
+		if tenantQuotaDetails != nil {
+			tenantQuotasList = openstackintel.GetQuotaLimits(tenantQuotaDetails)
+		} else {
+			// quota details extension is not available, only limits can be retrieved
+			tenantQuotasList, tenantErrors = openstackintel.GetQuotasPerTenant(networkClient, quotaTenantList, maxConcurrent)
+		}
+		logTenantErrors(tenantErrors)
+
+		if len(tenantErrors) > 0 && len(tenantErrors) == len(quotaTenantList) {
+			f := map[string]interface{}{"failedTenants": len(tenantErrors)}
+			return redact.New(fmt.Errorf("Retrieval of quotas failed for all tenants"), f)
+		}
+		return nil
+	})
+
+	var tenantLoadBalancerQuotas map[string]map[string]int64
+	fetch(lbaasQuotasFamily, func() serror.SnapError {
+		if octaviaClient == nil {
+			// quotas of Neutron LBaaS v2 are retrieved together with remaining Neutron quotas
+			return nil
+		}
+		var tenantErrors map[string]serror.SnapError
+		tenantLoadBalancerQuotas, tenantErrors = openstackintel.GetLoadBalancerQuotasPerTenant(octaviaClient, quotaTenantList, maxConcurrent)
+		logTenantErrors(tenantErrors)
+
+		if len(tenantErrors) > 0 && len(tenantErrors) == len(quotaTenantList) {
+			f := map[string]interface{}{"failedTenants": len(tenantErrors)}
+			return redact.New(fmt.Errorf("Retrieval of load balancer quotas failed for all tenants"), f)
+		}
+		return nil
+	})
+
+	done.Wait()
+
+	if calculateIPAvailability {
+		if failedFamily := getFailedFamily(ipAvailabilitySourceFamilies, failures); failedFamily != "" {
+			f := map[string]interface{}{"resourceFamily": ipAvailabilityFamily}
+			serr := redact.New(fmt.Errorf("IP availability cannot be calculated, collection of %s failed: %v", failedFamily, failures[failedFamily].Error()), f)
+			log.WithFields(serr.Fields()).Warn(serr.Error())
+			failures[ipAvailabilityFamily] = serr
+		} else {
+			ipAvailabilities = openstackintel.CalculateNetworkIPAvailabilities(tenantNetworks, tenantSubnets, tenantPorts)
+		}
+	}
+
+	// quotas of load balancing resources are maintained by Octavia if it is used
+	if tenantQuotasList != nil {
+		for tenantID, lbQuotas := range tenantLoadBalancerQuotas {
+			if tenantQuotasList[tenantID] == nil {
+				tenantQuotasList[tenantID] = map[string]int64{}
+			}
+			for name, limit := range lbQuotas {
+				if lbaasQuotas[name] {
+					tenantQuotasList[tenantID][name] = limit
+				}
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		failed := []string{}
+		for family := range failures {
+			failed = append(failed, family)
+		}
+		sort.Strings(failed)
+		log.WithFields(log.Fields{"failedResourceFamilies": strings.Join(failed, ",")}).Warn("Collection of some resource families failed, metrics are reported only for remaining ones")
+	}
+
+	data := tenantData{
+		counts: map[string]map[string]int64{
+			networksCountMetric:                tenantNetworks.Total,
+			subnetsCountMetric:                 tenantSubnets.Total,
+			routersCountMetric:                 tenantRouters.Total,
+			portsCountMetric:                   tenantPorts.Total,
+			floatingipsCountMetric:             tenantFloatingips.Total,
+			floatingipsAssociatedCountMetric:   tenantFloatingips.Associated,
+			floatingipsUnassociatedCountMetric: tenantFloatingips.Unassociated,
+			securityGroupsCountMetric:          tenantSecurityGroups.Total,
+			securityGroupRulesCountMetric:      tenantSecurityGroupRules.Total,
+			ikePoliciesCountMetric:             tenantIKEPolicies.Total,
+			ipsecPoliciesCountMetric:           tenantIPsecPolicies.Total,
+			vpnServicesCountMetric:             tenantVPNServices.Total,
+			ipsecSiteConnectionsCountMetric:    tenantIPsecSiteConnections.Total,
+			rbacPoliciesCountMetric:            tenantRBACPolicies.Total,
+			subnetPoolsCountMetric:             tenantSubnetPools.Total,
+			portsQoSPolicyCountMetric:          tenantPorts.QoSPolicy,
+			networksQoSPolicyCountMetric:       tenantNetworks.QoSPolicy,
+			qosPoliciesCountMetric:             tenantQoSPolicies.Total,
+			qosRulesCountMetric:                tenantQoSPolicies.Rules,
+			firewallGroupsCountMetric:          tenantFirewallGroups.Total,
+			firewallGroupPortsCountMetric:      tenantFirewallGroups.Ports,
+			firewallPoliciesCountMetric:        tenantFirewallPolicies.Total,
+			firewallRulesCountMetric:           tenantFirewallRules.Total,
+		},
+		unlisted: map[string][]tenantresources.TenantResource{
+			networksCountMetric:                tenantNetworks.Unlisted.Counted("Total", ""),
+			subnetsCountMetric:                 tenantSubnets.Unlisted.Counted("Total", ""),
+			routersCountMetric:                 tenantRouters.Unlisted.Counted("Total", ""),
+			portsCountMetric:                   tenantPorts.Unlisted.Counted("Total", ""),
+			floatingipsCountMetric:             tenantFloatingips.Unlisted.Counted("Total", ""),
+			floatingipsAssociatedCountMetric:   tenantFloatingips.Unlisted.Counted("Associated", ""),
+			floatingipsUnassociatedCountMetric: tenantFloatingips.Unlisted.Counted("Unassociated", ""),
+			securityGroupsCountMetric:          tenantSecurityGroups.Unlisted.Counted("Total", ""),
+			securityGroupRulesCountMetric:      tenantSecurityGroupRules.Unlisted.Counted("Total", ""),
+			ikePoliciesCountMetric:             tenantIKEPolicies.Unlisted.Counted("Total", ""),
+			ipsecPoliciesCountMetric:           tenantIPsecPolicies.Unlisted.Counted("Total", ""),
+			vpnServicesCountMetric:             tenantVPNServices.Unlisted.Counted("Total", ""),
+			ipsecSiteConnectionsCountMetric:    tenantIPsecSiteConnections.Unlisted.Counted("Total", ""),
+			rbacPoliciesCountMetric:            tenantRBACPolicies.Unlisted.Counted("Total", ""),
+			subnetPoolsCountMetric:             tenantSubnetPools.Unlisted.Counted("Total", ""),
+			portsQoSPolicyCountMetric:          tenantPorts.Unlisted.Counted("QoSPolicy", ""),
+			networksQoSPolicyCountMetric:       tenantNetworks.Unlisted.Counted("QoSPolicy", ""),
+			qosPoliciesCountMetric:             tenantQoSPolicies.Unlisted.Counted("Total", ""),
+			qosRulesCountMetric:                tenantQoSPolicies.Unlisted.Counted("Rules", ""),
+			firewallGroupsCountMetric:          tenantFirewallGroups.Unlisted.Counted("Total", ""),
+			firewallGroupPortsCountMetric:      tenantFirewallGroups.Unlisted.Counted("Ports", ""),
+			firewallPoliciesCountMetric:        tenantFirewallPolicies.Unlisted.Counted("Total", ""),
+			firewallRulesCountMetric:           tenantFirewallRules.Unlisted.Counted("Total", ""),
+		},
+		quotas:       tenantQuotasList,
+		quotaDetails: tenantQuotaDetails,
+		knownTenants: map[string]bool{},
+	}
+	for metricName, status := range portsStatusMetrics {
+		data.counts[metricName] = tenantPorts.Status[status]
+		data.unlisted[metricName] = tenantPorts.Unlisted.Counted("Status", status)
+	}
+	for metricName, class := range portsOwnerMetrics {
+		data.counts[metricName] = tenantPorts.Owner[class]
+		data.unlisted[metricName] = tenantPorts.Unlisted.Counted("Owner", class)
+	}
+	for metricName, status := range floatingipsStatusMetrics {
+		data.counts[metricName] = tenantFloatingips.Status[status]
+		data.unlisted[metricName] = tenantFloatingips.Unlisted.Counted("Status", status)
+	}
+	for metricName, status := range ipsecSiteConnectionsStatusMetrics {
+		data.counts[metricName] = tenantIPsecSiteConnections.Status[status]
+		data.unlisted[metricName] = tenantIPsecSiteConnections.Unlisted.Counted("Status", status)
+	}
+	for metricName, status := range firewallGroupsStatusMetrics {
+		data.counts[metricName] = tenantFirewallGroups.Status[status]
+		data.unlisted[metricName] = tenantFirewallGroups.Unlisted.Counted("Status", status)
+	}
+	for metricName, ruleType := range qosRulesTypeMetrics {
+		data.counts[metricName] = tenantQoSPolicies.RuleType[ruleType]
+		data.unlisted[metricName] = tenantQoSPolicies.Unlisted.Counted("RuleType", ruleType)
+	}
+	for metricName, objectType := range rbacPoliciesObjectTypeMetrics {
+		data.counts[metricName] = tenantRBACPolicies.ObjectType[objectType]
+		data.unlisted[metricName] = tenantRBACPolicies.Unlisted.Counted("ObjectType", objectType)
+	}
+	for metricName, action := range rbacPoliciesActionMetrics {
+		data.counts[metricName] = tenantRBACPolicies.Action[action]
+		data.unlisted[metricName] = tenantRBACPolicies.Unlisted.Counted("Action", action)
+	}
+	for metricName, m := range lbaasMetrics {
+		data.counts[metricName] = m.getCounts(tenantLoadBalancers)
+		data.unlisted[metricName] = m.getUnlisted(tenantLoadBalancers)
+	}
+	for _, tenant := range allTenants {
+		data.knownTenants[tenant.ID] = true
+	}
+	logOrphans := getConfigBool(metricTypes[0], cfgLogOrphanedResources)
+	// orphaned resources counted by requested metrics are gathered per family and logged once after all metrics are collected
+	orphans := map[string]map[string]tenantresources.TenantResource{}
+
+	metrics := []plugin.MetricType{}
+	for _, metricType := range metricTypes {
+
+		namespace := metricType.Namespace()
+		if isAgentNamespace(namespace) {
+			if serr, failed := failures[agentsFamily]; failed {
+				log.WithFields(log.Fields{"namespace": metricType.Namespace().String(), "resourceFamily": agentsFamily, "error": serr.Error()}).Debug("Metric skipped, collection of resource family failed")
+				continue
+			}
+			metrics = append(metrics, getAgentMetrics(namespace, agentList)...)
+			continue
+		}
+
+		if isResourceNamespace(namespace) {
+			families := getResourceMetricFamilies(namespace)
+			if len(families) == 0 {
+				f := map[string]interface{}{"namespace": metricType.Namespace().String()}
+				serr := redact.New(fmt.Errorf("Incorrect namespace, metric of network, subnet, router or subnet pool does not exist"), f)
+				log.WithFields(serr.Fields()).Warn(serr.String())
+				continue
+			}
+			if failedFamily := getFailedFamily(families, failures); failedFamily != "" {
+				log.WithFields(log.Fields{"namespace": metricType.Namespace().String(), "resourceFamily": failedFamily}).Debug("Metric skipped, collection of resource family failed")
+				continue
+			}
+
+			metricName := namespace[len(namespace)-1].Value
+			switch {
+			case isIPAvailabilityMetric(metricName):
+				metrics = append(metrics, getIPAvailabilityMetrics(namespace, tenantList, tenantNamespace, ipAvailabilities)...)
+			case isSubnetPoolMetric(metricName):
+				metrics = append(metrics, getResourceMetrics(namespace, tenantList, tenantNamespace, getSubnetPoolInfos(tenantSubnetPools.Pools), getSubnetPoolValues(metricName, tenantSubnetPools.Pools, tenantSubnets.PerSubnetPool))...)
+			case metricName == interfacesCountMetric:
+				metrics = append(metrics, getResourceMetrics(namespace, tenantList, tenantNamespace, getRouterInfos(tenantRouters.Routers), getCountValues(tenantPorts.RouterInterfaces))...)
+			case metricName == portsCountMetric:
+				metrics = append(metrics, getResourceMetrics(namespace, tenantList, tenantNamespace, getNetworkInfos(tenantNetworks.Networks), getCountValues(tenantPorts.PerNetwork))...)
+			case metricName == subnetsCountMetric:
+				metrics = append(metrics, getResourceMetrics(namespace, tenantList, tenantNamespace, getNetworkInfos(tenantNetworks.Networks), getCountValues(tenantSubnets.PerNetwork))...)
+			}
+			continue
+		}
+
+		if len(namespace) != nsLength {
+			f := map[string]interface{}{"namespace": metricType.Namespace().String()}
+			serr := redact.New(fmt.Errorf("Incorrect namespace length"), f)
+			log.WithFields(serr.Fields()).Warn(serr.String())
+			continue
+		}
+
+		tenantElement := namespace[tenantNameNSPartNumber].Value
+		metricName := namespace[metricNameNSPartNumber].Value
+
+		if tenantElement == errorsNSPart {
+			if !isResourceFamily(metricName) {
+				f := map[string]interface{}{"namespace": metricType.Namespace().String()}
+				serr := redact.New(fmt.Errorf("Incorrect namespace, resource family does not exist"), f)
+				log.WithFields(serr.Fields()).Warn(serr.String())
+				continue
+			}
+			metric := plugin.MetricType{
+				Timestamp_: time.Now(),
+				Namespace_: namespace,
+				Data_:      int64(0),
+			}
+			if serr, failed := failures[metricName]; failed {
+				metric.Data_ = int64(1)
+				metric.Tags_ = map[string]string{"error": serr.Error()}
+			}
+			metrics = append(metrics, metric)
+			continue
+		}
+
+		if tenantElement == aggregateNSPart {
+			if !isAggregateMetric(metricName) {
+				f := map[string]interface{}{"namespace": metricType.Namespace().String()}
+				serr := redact.New(fmt.Errorf("Incorrect namespace, aggregate metric does not exist"), f)
+				log.WithFields(serr.Fields()).Warn(serr.String())
+				continue
+			}
+			if failedFamily := getFailedFamily(getRequiredFamilies("", metricName), failures); failedFamily != "" {
+				log.WithFields(log.Fields{"namespace": metricType.Namespace().String(), "resourceFamily": failedFamily}).Debug("Metric skipped, collection of resource family failed")
+				continue
+			}
+			if family := getUnavailableFamily(getRequiredFamilies("", metricName), unavailable); family != "" {
+				log.WithFields(log.Fields{"namespace": metricType.Namespace().String(), "resourceFamily": family}).Debug("Metric skipped, resource family is not available")
+				continue
+			}
+			val := data.getTotal(metricName)
+			if strings.HasPrefix(metricName, orphaned) {
+				countMetric := metricName[len(orphaned):]
+				val = data.getOrphaned(countMetric)
+				if logOrphans && val > 0 {
+					family := getCountFamily(countMetric)
+					if orphans[family] == nil {
+						orphans[family] = map[string]tenantresources.TenantResource{}
+					}
+					for _, r := range data.unlisted[countMetric] {
+						orphans[family][r.ID] = r
+					}
+				}
+			}
+			metrics = append(metrics, plugin.MetricType{
+				Timestamp_: time.Now(),
+				Namespace_: namespace,
+				Data_:      val,
+			})
+			continue
+		}
+
+		if !isTenantMetric(metricName) {
+			f := map[string]interface{}{"namespace": "/" + metricType.Namespace().String()}
+			serr := redact.New(fmt.Errorf("Incorrect namespace, metric does not exist"), f)
+			log.WithFields(serr.Fields()).Warn(serr.String())
+			continue
+		}
+
+		if failedFamily := getFailedFamily(getRequiredFamilies("", metricName), failures); failedFamily != "" {
+			log.WithFields(log.Fields{"namespace": metricType.Namespace().String(), "resourceFamily": failedFamily}).Debug("Metric skipped, collection of resource family failed")
+			continue
+		}
+		if family := getUnavailableFamily(getRequiredFamilies("", metricName), unavailable); family != "" {
+			log.WithFields(log.Fields{"namespace": metricType.Namespace().String(), "resourceFamily": family}).Debug("Metric skipped, resource family is not available")
+			continue
+		}
+
+		for _, tenant := range resolveTenants(tenantList, tenantElement, tenantNamespace) {
+			val, ok := data.getValue(tenant.ID, metricName)
+			if !ok {
+				f := map[string]interface{}{"namespace": metricType.Namespace().String(), "tenantName": tenant.Name, "tenantID": tenant.ID}
+				serr := redact.New(fmt.Errorf("Incorrect namespace, metric with specified namespace does not exist"), f)
+				if tenantElement == tenantWildcard {
+					// quota may not be defined for every tenant matched by wildcard
+					log.WithFields(serr.Fields()).Debug(serr.String())
+				} else {
+					log.WithFields(serr.Fields()).Warn(serr.String())
+				}
+				continue
+			}
+
+			ns := make(core.Namespace, len(namespace))
+			copy(ns, namespace)
+			ns[tenantNameNSPartNumber].Value = getTenantElementValue(tenant, tenantNamespace)
+
+			metrics = append(metrics, plugin.MetricType{
+				Timestamp_: time.Now(),
+				Namespace_: ns,
+				Data_:      val,
+				Tags_:      getTenantTags(tenant),
+			})
+		}
+	}
+
+	for family, resources := range orphans {
+		logOrphanedResources(family, resources)
+	}
+	return metrics, nil
+}
+
+// GetConfigPolicy returns config policy
+// It returns error in case retrieval was not successful
+func (c *Collector) GetConfigPolicy() (*cpolicy.ConfigPolicy, error) {
+	cp := cpolicy.New()
+	config := cpolicy.NewPolicyNode()
+
+	r1, err := cpolicy.NewStringRule(cfgURL, true)
+	if err != nil {
+		return cp, err
+	}
+	r1.Description = "URL for OpenStack Identity endpoint"
+	config.Add(r1)
+
+	r2, err := cpolicy.NewStringRule(cfgUser, false)
+	if err != nil {
+		return cp, err
+	}
+	r2.Description = "user name used to authenticate"
+	config.Add(r2)
+
+	r3, err := cpolicy.NewStringRule(cfgPassword, false)
+	if err != nil {
+		return cp, err
+	}
+	r3.Description = "password used to authenticate"
+	config.Add(r3)
+
+	r4, err := cpolicy.NewStringRule(cfgTenant, false)
+	if err != nil {
+		return cp, err
+	}
+	r4.Description = " tenant name used to authenticate"
+	config.Add(r4)
+
+	r5, err := cpolicy.NewStringRule(cfgAppCredentialID, false)
+	if err != nil {
+		return cp, err
+	}
+	r5.Description = "ID of application credential used to authenticate instead of password"
+	config.Add(r5)
+
+	r6, err := cpolicy.NewStringRule(cfgAppCredentialName, false)
+	if err != nil {
+		return cp, err
+	}
+	r6.Description = "name of application credential used to authenticate instead of password (requires user name)"
+	config.Add(r6)
+
+	r7, err := cpolicy.NewStringRule(cfgAppCredentialSecret, false)
+	if err != nil {
+		return cp, err
+	}
+	r7.Description = "secret of application credential used to authenticate"
+	config.Add(r7)
+
+	r8, err := cpolicy.NewIntegerRule(cfgPageSize, false, defaultPageSize)
+	if err != nil {
+		return cp, err
+	}
+	r8.Description = "maximum number of resources retrieved from Neutron in a single request"
+	config.Add(r8)
+
+	r9, err := cpolicy.NewBoolRule(cfgTenantIDFieldsOnly, false, false)
+	if err != nil {
+		return cp, err
+	}
+	r9.Description = "retrieve only IDs and tenant IDs of counted resources to reduce size of Neutron responses"
+	config.Add(r9)
+
+	r10, err := cpolicy.NewIntegerRule(cfgMaxConcurrentRequests, false, defaultMaxConcurrentRequests)
+	if err != nil {
+		return cp, err
+	}
+	r10.Description = "maximum number of parallel per-tenant requests (e.g. quota lookups) sent to Neutron"
+	config.Add(r10)
+
+	r11, err := cpolicy.NewStringRule(cfgTenantNamespace, false, tenantNamespaceName)
+	if err != nil {
+		return cp, err
+	}
+	r11.Description = "identify tenants in namespace by 'name' or by 'id' (IDs are unique across Keystone domains)"
+	config.Add(r11)
+
+	r12, err := cpolicy.NewStringRule(cfgTenantInclude, false)
+	if err != nil {
+		return cp, err
+	}
+	r12.Description = "regular expression which has to match whole name of monitored tenant"
+	config.Add(r12)
+
+	r13, err := cpolicy.NewStringRule(cfgTenantExclude, false)
+	if err != nil {
+		return cp, err
+	}
+	r13.Description = "regular expression which excludes tenants with matching whole name from monitoring"
+	config.Add(r13)
+
+	r14, err := cpolicy.NewStringRule(cfgDomainFilter, false)
+	if err != nil {
+		return cp, err
+	}
+	r14.Description = "regular expression which has to match whole domain ID of monitored project (Identity API v3 only)"
+	config.Add(r14)
+
+	r15, err := cpolicy.NewBoolRule(cfgLogOrphanedResources, false, false)
+	if err != nil {
+		return cp, err
+	}
+	r15.Description = "log IDs of resources owned by tenants which do not exist in Keystone when orphaned_* metrics are collected"
+	config.Add(r15)
+
+	cp.Add([]string{""}, config)
+	return cp, nil
+}
+
+//authenticate creates authenticated provider client based on credentials from configuration, if it does not exist yet
+func (c *Collector) authenticate(cfg interface{}) error {
+	creds, err := getCredentials(cfg)
+	if err != nil {
+		return err
+	}
+
+	if c.provider != nil {
+		return nil
+	}
+
+	var provider *gophercloud.ProviderClient
+	var serr serror.SnapError
+	if creds.isApplicationCredential() {
+		provider, serr = openstackintel.AuthenticateWithApplicationCredential(creds.endpoint, creds.appCredentialID, creds.appCredentialName, creds.appCredentialSecret, creds.user, creds.domainName, creds.domainID)
+	} else {
+		provider, serr = openstackintel.Authenticate(creds.endpoint, creds.user, creds.password, creds.tenant, creds.domainName, creds.domainID)
+	}
+	if serr != nil {
+		log.WithFields(serr.Fields()).Warn(serr.Error())
+		return serr
+	}
+	c.provider = provider
+	return nil
+}
+
+//credentials contains configuration items used to authenticate
+type credentials struct {
+	endpoint            string
+	user                string
+	password            string
+	tenant              string
+	domainName          string
+	domainID            string
+	appCredentialID     string
+	appCredentialName   string
+	appCredentialSecret string
+}
+
+//isApplicationCredential checks whether application credential is used instead of password
+func (c credentials) isApplicationCredential() bool {
+	return c.appCredentialID != "" || c.appCredentialName != "" || c.appCredentialSecret != ""
+}
+
+//getCredentials retrieves credentials from configuration, exactly one complete credential set (password or application credential) is required
+func getCredentials(cfg interface{}) (credentials, error) {
+	items, err := config.GetConfigItems(cfg, cfgURL)
+	if err != nil {
+		return credentials{}, err
+	}
+
+	creds := credentials{
+		endpoint:            items[cfgURL].(string),
+		user:                getConfigString(cfg, cfgUser),
+		password:            getConfigString(cfg, cfgPassword),
+		tenant:              getConfigString(cfg, cfgTenant),
+		domainName:          getConfigString(cfg, cfgDomainName),
+		domainID:            getConfigString(cfg, cfgDomainID),
+		appCredentialID:     getConfigString(cfg, cfgAppCredentialID),
+		appCredentialName:   getConfigString(cfg, cfgAppCredentialName),
+		appCredentialSecret: getConfigString(cfg, cfgAppCredentialSecret),
+	}
+
+	missing := []string{}
+	if creds.isApplicationCredential() {
+		if creds.password != "" || creds.tenant != "" {
+			return creds, fmt.Errorf("Exactly one credential set is required, both password (%s, %s) and application credential are provided", cfgPassword, cfgTenant)
+		}
+		if creds.appCredentialID == "" && creds.appCredentialName == "" {
+			missing = append(missing, cfgAppCredentialID+" or "+cfgAppCredentialName)
+		}
+		if creds.appCredentialSecret == "" {
+			missing = append(missing, cfgAppCredentialSecret)
+		}
+		if creds.appCredentialID == "" && creds.appCredentialName != "" && creds.user == "" {
+			missing = append(missing, cfgUser)
+		}
+	} else {
+		for _, item := range []struct{ name, value string }{{cfgUser, creds.user}, {cfgPassword, creds.password}, {cfgTenant, creds.tenant}} {
+			if item.value == "" {
+				missing = append(missing, item.name)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return creds, fmt.Errorf("Incomplete credentials, missing configuration items: %s", strings.Join(missing, ", "))
+	}
+	return creds, nil
+}
+
+//getConfigString returns value of string configuration item or empty string if item is not set
+func getConfigString(cfg interface{}, name string) string {
+	item, err := config.GetConfigItem(cfg, name)
+	if err != nil {
+		return ""
+	}
+	value, ok := item.(string)
+	if !ok {
+		return ""
+	}
+	return value
+}
+
+//getConfigBool returns value of boolean configuration item or false if item is not set
+func getConfigBool(cfg interface{}, name string) bool {
+	item, err := config.GetConfigItem(cfg, name)
+	if err != nil {
+		return false
+	}
+	value, ok := item.(bool)
+	return ok && value
+}
+
+//getListOpts returns options of listing resources counted per tenant based on configuration
+func getListOpts(cfg interface{}) *tenantresources.ListOpts {
+	opts := &tenantresources.ListOpts{Limit: defaultPageSize}
+
+	if item, err := config.GetConfigItem(cfg, cfgPageSize); err == nil {
+		if pageSize, ok := item.(int); ok && pageSize >= 0 {
+			opts.Limit = pageSize
+		}
+	}
+
+	if item, err := config.GetConfigItem(cfg, cfgTenantIDFieldsOnly); err == nil {
+		if fieldsOnly, ok := item.(bool); ok && fieldsOnly {
+			opts.Fields = tenantresources.TenantIDFields
+		}
+	}
+	return opts
+}
+
+//getMaxConcurrentRequests returns maximum number of parallel per-tenant requests based on configuration
+func getMaxConcurrentRequests(cfg interface{}) int {
+	item, err := config.GetConfigItem(cfg, cfgMaxConcurrentRequests)
+	if err != nil {
+		return defaultMaxConcurrentRequests
+	}
+	maxConcurrent, ok := item.(int)
+	if !ok || maxConcurrent < 1 {
+		return defaultMaxConcurrentRequests
+	}
+	return maxConcurrent
+}
+
+//logTenantErrors logs errors of tenants (or other resources, e.g. load balancer pools) which were skipped
+func logTenantErrors(tenantErrors map[string]serror.SnapError) {
+	for _, serr := range tenantErrors {
+		log.WithFields(serr.Fields()).Warn(serr.Error())
+	}
+}
+
+//isResourceFamily checks whether given name is a name of resource family
+func isResourceFamily(name string) bool {
+	for _, family := range resourceFamilies {
+		if family == name {
+			return true
+		}
+	}
+	return false
+}
+
+//getFailedFamily returns name of resource family which is required by metric and which collection failed, empty string if there is no such family
+func getFailedFamily(families []string, failures map[string]serror.SnapError) string {
+	for _, family := range families {
+		if _, failed := failures[family]; failed {
+			return family
+		}
+	}
+	return ""
+}
+
+//discoverExtensions checks by extension discovery whether extensions of requested resource families are loaded, each extension is checked once
+//It returns families whose extension is not loaded and failures of families whose extension could not be checked
+func discoverExtensions(client *gophercloud.ServiceClient, requestedFamilies map[string]bool, extensions map[string]string) (map[string]bool, map[string]serror.SnapError) {
+	unavailable := map[string]bool{}
+	failures := map[string]serror.SnapError{}
+
+	type discovery struct {
+		available bool
+		serr      serror.SnapError
+	}
+	discovered := map[string]discovery{}
+
+	for family, alias := range extensions {
+		if !requestedFamilies[family] {
+			continue
+		}
+		d, ok := discovered[alias]
+		if !ok {
+			d.available, d.serr = openstackintel.IsExtensionAvailable(client, alias)
+			discovered[alias] = d
+		}
+
+		switch {
+		case d.serr != nil:
+			serr := redact.New(d.serr, mergeFields(d.serr.Fields(), map[string]interface{}{"resourceFamily": family}))
+			log.WithFields(serr.Fields()).Warn(serr.Error())
+			failures[family] = serr
+		case !d.available:
+			log.WithFields(log.Fields{"extension": alias, "resourceFamily": family}).Debug("Neutron extension is not loaded, metrics of resource family are skipped")
+			unavailable[family] = true
+		}
+	}
+	return unavailable, failures
+}
+
+//getUnavailableFamily returns name of the first of families which is not available (e.g. extension is not loaded), empty string if all are available
+func getUnavailableFamily(families []string, unavailable map[string]bool) string {
+	for _, family := range families {
+		if unavailable[family] {
+			return family
+		}
+	}
+	return ""
+}
+
+//getRequiredFamilies returns names of resource families which have to be retrieved to calculate metric
+func getRequiredFamilies(tenantElement string, metricName string) []string {
+	if tenantElement == errorsNSPart {
+		return []string{metricName}
+	}
+
+	switch {
+	case strings.HasPrefix(metricName, quotas):
+		return getQuotaFamilies(metricName[len(quotas):])
+	case strings.HasPrefix(metricName, utilization):
+		return append(getQuotaFamilies(metricName[len(utilization):]), getUsageFamily(metricName[len(utilization):])...)
+	case strings.HasPrefix(metricName, headroom):
+		return append(getQuotaFamilies(metricName[len(headroom):]), getUsageFamily(metricName[len(headroom):])...)
+	case strings.HasPrefix(metricName, orphaned):
+		return []string{getCountFamily(metricName[len(orphaned):])}
+	default:
+		return []string{getCountFamily(metricName)}
+	}
+}
+
+//getQuotaFamilies returns names of resource families which provide quota, quotas of load balancing resources may be provided by Octavia
+func getQuotaFamilies(quotaName string) []string {
+	if lbaasQuotas[quotaName] {
+		return []string{quotasFamily, lbaasQuotasFamily}
+	}
+	return []string{quotasFamily}
+}
+
+//getUsageFamily returns name of resource family which is used to calculate usage of quota, empty slice if usage of quota is not calculated
+func getUsageFamily(quotaName string) []string {
+	countMetric, ok := quotaUsageMetrics[quotaName]
+	if !ok {
+		return []string{}
+	}
+	return []string{getCountFamily(countMetric)}
+}
+
+//getCountFamily returns name of resource family which is counted by metric
+func getCountFamily(countMetric string) string {
+	if _, ok := lbaasMetrics[countMetric]; ok {
+		return lbaasFamily
+	}
+	if resource, ok := countMetricResources[countMetric]; ok {
+		if family, ok := collectionFamilies[resource]; ok {
+			return family
+		}
+		return resource.Key
+	}
+	return strings.TrimSuffix(countMetric, countSuffix)
+}
+
+//getDemand returns resource families and tenant namespace elements required by requested metrics
+func getDemand(metricTypes []plugin.MetricType) (map[string]bool, map[string]bool) {
+	families := map[string]bool{}
+	tenantElements := map[string]bool{}
+
+	for _, metricType := range metricTypes {
+		namespace := metricType.Namespace()
+		if isAgentNamespace(namespace) {
+			families[agentsFamily] = true
+			continue
+		}
+		if isResourceNamespace(namespace) {
+			for _, family := range getResourceMetricFamilies(namespace) {
+				families[family] = true
+			}
+			continue
+		}
+		if len(namespace) != nsLength {
+			continue
+		}
+
+		tenantElement := namespace[tenantNameNSPartNumber].Value
+		for _, family := range getRequiredFamilies(tenantElement, namespace[metricNameNSPartNumber].Value) {
+			families[family] = true
+		}
+		if tenantElement != errorsNSPart && tenantElement != aggregateNSPart {
+			tenantElements[tenantElement] = true
+		}
 	}
+	return families, tenantElements
+}
 
-	// Generate available namespace from tenants (user counts per tenant)
-	for _, tenant := range allTenants {
+//filterTenants returns tenants matching given tenant namespace elements
+func filterTenants(tenantList []types.Tenant, tenantElements map[string]bool, tenantNamespace string) []types.Tenant {
+	filtered := []types.Tenant{}
+	for _, tenant := range tenantList {
+		if tenantElements[getTenantElementValue(tenant, tenantNamespace)] || tenantElements[tenantWildcard] {
+			filtered = append(filtered, tenant)
+		}
+	}
+	return filtered
+}
 
-		q, serr := openstackintel.GetQuotasForTenant(networkClient, tenant.ID)
-		if serr != nil {
-			log.WithFields(serr.Fields()).Warn(serr.Error())
-			return nil, serr
+//mergeFields returns fields extended with additional ones
+func mergeFields(fields map[string]interface{}, additional map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	for k, v := range additional {
+		merged[k] = v
+	}
+	return merged
+}
+
+//tenantData holds resources retrieved from Neutron which are used to calculate tenant metrics
+type tenantData struct {
+	counts map[string]map[string]int64
+	//unlisted holds resources counted by metric which are owned by tenants which do not exist in Keystone
+	unlisted     map[string][]tenantresources.TenantResource
+	quotas       map[string]map[string]int64
+	quotaDetails map[string]map[string]tenantquotas.QuotaDetails
+	knownTenants map[string]bool
+}
+
+//getValue returns value of metric for tenant with given ID
+func (d tenantData) getValue(tenantID, metricName string) (interface{}, bool) {
+	switch {
+	case strings.HasPrefix(metricName, quotas):
+		return getQuotaValue(d.quotas, d.quotaDetails, tenantID, metricName[len(quotas):])
+	case strings.HasPrefix(metricName, utilization):
+		used, limit, ok := getQuotaUsage(d.counts, d.quotas, tenantID, metricName[len(utilization):])
+		return quotaUtilization(used, limit), ok
+	case strings.HasPrefix(metricName, headroom):
+		used, limit, ok := getQuotaUsage(d.counts, d.quotas, tenantID, metricName[len(headroom):])
+		return quotaHeadroom(used, limit), ok
+	default:
+		val, ok := d.counts[metricName][tenantID]
+		return val, ok
+	}
+}
+
+//getTotal returns number of resources counted by metric in the whole cloud, resources of unknown tenants are included
+func (d tenantData) getTotal(metricName string) int64 {
+	total := int64(0)
+	for _, count := range d.counts[metricName] {
+		total += count
+	}
+	return total
+}
+
+//getOrphaned returns number of resources counted by metric which are owned by tenants which do not exist in Keystone, resources without tenant are not included
+func (d tenantData) getOrphaned(metricName string) int64 {
+	total := int64(0)
+	for tenantID, count := range d.counts[metricName] {
+		if tenantID != "" && !d.knownTenants[tenantID] {
+			total += count
 		}
+	}
+	return total
+}
 
-		for k := range q {
-			info := getInfoFields(quotas + k)
-			mts = append(mts, plugin.MetricType{
+//isTenantMetric checks whether metric with given name can be collected for a tenant
+func isTenantMetric(metricName string) bool {
+	if strings.HasPrefix(metricName, quotas) || strings.HasPrefix(metricName, utilization) || strings.HasPrefix(metricName, headroom) {
+		return true
+	}
+	return isCountMetric(metricName)
+}
 
-				Namespace_:   core.NewNamespace(vendor, openstack, pluginName, tenant.Name, quotas+k),
-				Config_:      cfg.ConfigDataNode,
-				Description_: info.description,
-				Unit_:        info.unit,
-			})
+//isCountMetric checks whether metric with given name indicates number of resources
+func isCountMetric(metricName string) bool {
+	if _, ok := lbaasMetrics[metricName]; ok {
+		return true
+	}
+	for _, m := range neutronConstMetrics {
+		if m == metricName {
+			return true
 		}
+	}
+	return false
+}
 
-		for m := range neutronConstMetrics {
-			info := getInfoFields(neutronConstMetrics[m])
-			mts = append(mts, plugin.MetricType{
-				Namespace_:   core.NewNamespace(vendor, openstack, pluginName, tenant.Name, neutronConstMetrics[m]),
-				Config_:      cfg.ConfigDataNode,
-				Description_: info.description,
-				Unit_:        info.unit,
-			})
+//getCountMetrics returns names of all metrics which indicate number of resources
+func getCountMetrics() []string {
+	metricNames := []string{}
+	metricNames = append(metricNames, neutronConstMetrics...)
+	return append(metricNames, lbaasMetricNames...)
+}
+
+//getLBaaSMetrics returns names of metrics which indicate number of load balancing resources and resources counted by them,
+//e.g. members_operating_error_count indicates number of pool members in ERROR operating status
+func getLBaaSMetrics() ([]string, map[string]lbaasMetric) {
+	metricNames := []string{}
+	metrics := map[string]lbaasMetric{}
+	add := func(metricName string, m lbaasMetric) {
+		metricNames = append(metricNames, metricName)
+		metrics[metricName] = m
+	}
+
+	for _, collection := range lbaasCollections {
+		add(collection+countSuffix, lbaasMetric{collection: collection})
+		for _, status := range openstackintel.LBProvisioningStatuses {
+			add(collection+"_provisioning_"+strings.ToLower(status)+countSuffix, lbaasMetric{collection: collection, provisioningStatus: status})
+		}
+		for _, status := range openstackintel.LBOperatingStatuses {
+			add(collection+"_operating_"+strings.ToLower(status)+countSuffix, lbaasMetric{collection: collection, operatingStatus: status})
 		}
 	}
-	return mts, nil
+	return metricNames, metrics
 }
 
-// CollectMetrics returns list of requested metric values
-// It returns error in case retrieval was not successful
-func (c *Collector) CollectMetrics(metricTypes []plugin.MetricType) ([]plugin.MetricType, error) {
-	items, err := config.GetConfigItems(metricTypes[0], cfgURL, cfgUser, cfgPassword, cfgTenant)
-	if err != nil {
-		return nil, err
+//getCounts returns numbers of load balancing resources counted by metric keyed by tenant ID
+func (m lbaasMetric) getCounts(lbCounts openstackintel.LoadBalancerCounts) map[string]int64 {
+	counts := lbCounts[m.collection]
+	switch {
+	case m.provisioningStatus != "":
+		return counts.ProvisioningStatus[m.provisioningStatus]
+	case m.operatingStatus != "":
+		return counts.OperatingStatus[m.operatingStatus]
+	default:
+		return counts.Total
 	}
+}
+
+//getUnlisted returns load balancing resources counted by metric which are owned by tenants which do not exist in Keystone
+func (m lbaasMetric) getUnlisted(lbCounts openstackintel.LoadBalancerCounts) []tenantresources.TenantResource {
+	unlisted := lbCounts[m.collection].Unlisted
+	switch {
+	case m.provisioningStatus != "":
+		return unlisted.Counted("ProvisioningStatus", m.provisioningStatus)
+	case m.operatingStatus != "":
+		return unlisted.Counted("OperatingStatus", m.operatingStatus)
+	default:
+		return unlisted.Counted("Total", "")
+	}
+}
 
-	domain_name := ""
-	domain_id := ""
+//getDescription returns description of metric which indicates number of load balancing resources
+func (m lbaasMetric) getDescription() string {
+	description := "number of tenant " + lbaasResourceNames[m.collection]
+	switch {
+	case m.provisioningStatus != "":
+		return fmt.Sprintf("%s in %s provisioning status", description, m.provisioningStatus)
+	case m.operatingStatus != "":
+		return fmt.Sprintf("%s in %s operating status", description, m.operatingStatus)
+	default:
+		return description
+	}
+}
+
+//isAggregateMetric checks whether metric with given name can be collected for the whole cloud
+func isAggregateMetric(metricName string) bool {
+	return isCountMetric(metricName) || strings.HasPrefix(metricName, orphaned) && isCountMetric(metricName[len(orphaned):])
+}
 
-	endpoint := items[cfgURL].(string)
-	user := items[cfgUser].(string)
-	password := items[cfgPassword].(string)
-	tenant := items[cfgTenant].(string)
-	dom_name, _ := config.GetConfigItem(metricTypes[0], "domain_name")
-	dom_id, _ := config.GetConfigItem(metricTypes[0], "domain_id")
-	if dom_name != nil {
-		domain_name = dom_name.(string)
+//logOrphanedResources logs IDs of resources of family owned by tenants which do not exist in Keystone, resources are keyed by ID and logged in its order
+func logOrphanedResources(family string, resources map[string]tenantresources.TenantResource) {
+	ids := []string{}
+	for id := range resources {
+		ids = append(ids, id)
 	}
-	if dom_id != nil {
-		domain_id = dom_id.(string)
+	sort.Strings(ids)
+	for _, id := range ids {
+		log.WithFields(log.Fields{"resourceFamily": family, "id": id, "tenantID": resources[id].TenantID}).Info("Resource owned by tenant which does not exist in Keystone")
 	}
+}
 
-	if c.provider == nil {
-		provider, serr := openstackintel.Authenticate(endpoint, user, password, tenant, domain_name, domain_id)
-		if serr != nil {
-			log.WithFields(serr.Fields()).Warn(serr.Error())
-			return nil, serr
-		}
-		c.provider = provider
+//resolveTenants returns tenants matching tenant namespace element, wildcard matches all tenants
+func resolveTenants(tenantList []types.Tenant, tenantElement string, tenantNamespace string) []types.Tenant {
+	if tenantElement == tenantWildcard {
+		return tenantList
 	}
 
-	identityClient := openstackgophercloud.NewIdentityV2(c.provider)
-	tenantList, serr := openstackintel.GetAllTenants(identityClient)
-	if serr != nil {
-		log.WithFields(serr.Fields()).Warn(serr.Error())
-		return nil, serr
+	resolved := []types.Tenant{}
+	for _, tenant := range tenantList {
+		if getTenantElementValue(tenant, tenantNamespace) == tenantElement {
+			resolved = append(resolved, tenant)
+		}
 	}
+	if len(resolved) == 0 {
+		f := map[string]interface{}{"tenant": tenantElement, "tenantNamespace": tenantNamespace}
+		serr := redact.New(fmt.Errorf("Incorrect namespace, tenant does not exist"), f)
+		log.WithFields(serr.Fields()).Warn(serr.String())
+	}
+	return resolved
+}
 
-	networkClient, err := openstackgophercloud.NewNetworkV2(c.provider, gophercloud.EndpointOpts{})
-	if err != nil {
-		return nil, err
+//isAgentNamespace checks whether namespace is a namespace of metric of Neutron agent
+func isAgentNamespace(namespace core.Namespace) bool {
+	if len(namespace) != agentNSLength && len(namespace) != agentTypeNSLength {
+		return false
 	}
+	return namespace[tenantNameNSPartNumber].Value == agentsNSPart
+}
 
-	var done sync.WaitGroup
-	done.Add(6)
+//getAgentMetrics returns metrics of agents (or agent types) matching namespace, agent type and host elements can be wildcards
+func getAgentMetrics(namespace core.Namespace, agentList []agents.Agent) []plugin.MetricType {
+	metricName := namespace[len(namespace)-1].Value
+	typeElement := namespace[agentTypeNSPartNumber].Value
 
-	var tenantNetworks map[string]int64
-	go func() {
-		var serr serror.SnapError
-		tenantNetworks, serr = openstackintel.GetNetworkCountPerTenant(networkClient, tenantList)
-		if serr != nil {
-			log.WithFields(serr.Fields()).Warn(serr.Error())
-			panic(serr)
+	if len(namespace) == agentTypeNSLength {
+		if metricName != agentsAliveCountMetric && metricName != agentsDownCountMetric {
+			f := map[string]interface{}{"namespace": namespace.String()}
+			serr := redact.New(fmt.Errorf("Incorrect namespace, agent type metric does not exist"), f)
+			log.WithFields(serr.Fields()).Warn(serr.String())
+			return []plugin.MetricType{}
 		}
-		done.Done()
-	}()
+		return getAgentTypeMetrics(namespace, agentList)
+	}
 
-	var tenantSubnets map[string]int64
-	go func() {
-		var serr serror.SnapError
-		tenantSubnets, serr = openstackintel.GetSubnetsCountPerTenant(networkClient, tenantList)
-		if serr != nil {
-			log.WithFields(serr.Fields()).Warn(serr.Error())
-			panic(serr)
+	hostElement := namespace[hostNSPartNumber].Value
+	metrics := []plugin.MetricType{}
+	for _, agent := range agentList {
+		agentType := getAgentTypeElementValue(agent.AgentType)
+		if (typeElement != tenantWildcard && typeElement != agentType) || (hostElement != tenantWildcard && hostElement != agent.Host) {
+			continue
 		}
-		done.Done()
-	}()
 
-	var tenantRouters map[string]int64
-	go func() {
-		var serr serror.SnapError
-		tenantRouters, serr = openstackintel.GetRoutersCountPerTenant(networkClient, tenantList)
-		if serr != nil {
-			log.WithFields(serr.Fields()).Warn(serr.Error())
-			panic(serr)
+		var val interface{}
+		switch metricName {
+		case agentAliveMetric:
+			val = boolToInt64(agent.Alive)
+		case agentAdminStateUpMetric:
+			val = boolToInt64(agent.AdminStateUp)
+		case agentHeartbeatAgeMetric:
+			heartbeat, err := agent.Heartbeat()
+			if err != nil {
+				log.WithFields(log.Fields{"agentID": agent.ID, "host": agent.Host}).Debug(err.Error())
+				continue
+			}
+			val = int64(time.Since(heartbeat).Seconds())
+		default:
+			f := map[string]interface{}{"namespace": namespace.String()}
+			serr := redact.New(fmt.Errorf("Incorrect namespace, agent metric does not exist"), f)
+			log.WithFields(serr.Fields()).Warn(serr.String())
+			return []plugin.MetricType{}
 		}
-		done.Done()
-	}()
 
-	var tenantPorts map[string]int64
-	go func() {
-		var serr serror.SnapError
-		tenantPorts, serr = openstackintel.GetPortsCountPerTenant(networkClient, tenantList)
-		if serr != nil {
-			log.WithFields(serr.Fields()).Warn(serr.Error())
-			panic(serr)
+		ns := make(core.Namespace, len(namespace))
+		copy(ns, namespace)
+		ns[agentTypeNSPartNumber].Value = agentType
+		ns[hostNSPartNumber].Value = agent.Host
+
+		metrics = append(metrics, plugin.MetricType{
+			Timestamp_: time.Now(),
+			Namespace_: ns,
+			Data_:      val,
+			Tags_: map[string]string{
+				"agent_id":          agent.ID,
+				"agent_type":        agent.AgentType,
+				"binary":            agent.Binary,
+				"availability_zone": agent.AvailabilityZone,
+			},
+		})
+	}
+	return metrics
+}
+
+//getAgentTypeMetrics returns numbers of alive or down agents of types matching namespace
+func getAgentTypeMetrics(namespace core.Namespace, agentList []agents.Agent) []plugin.MetricType {
+	typeElement := namespace[agentTypeNSPartNumber].Value
+	alive := namespace[len(namespace)-1].Value == agentsAliveCountMetric
+
+	counts := map[string]int64{}
+	agentTypes := map[string]string{}
+	for _, agent := range agentList {
+		agentType := getAgentTypeElementValue(agent.AgentType)
+		if typeElement != tenantWildcard && typeElement != agentType {
+			continue
 		}
-		done.Done()
-	}()
+		agentTypes[agentType] = agent.AgentType
+		if agent.Alive == alive {
+			counts[agentType]++
+		}
+	}
 
-	var tenantFloatingips map[string]int64
-	go func() {
-		var serr serror.SnapError
-		tenantFloatingips, serr = openstackintel.GetFloatingIPsCountPerTenant(networkClient, tenantList)
-		if serr != nil {
-			log.WithFields(serr.Fields()).Warn(serr.Error())
-			panic(serr)
+	sorted := []string{}
+	for agentType := range agentTypes {
+		sorted = append(sorted, agentType)
+	}
+	sort.Strings(sorted)
+
+	metrics := []plugin.MetricType{}
+	for _, agentType := range sorted {
+		ns := make(core.Namespace, len(namespace))
+		copy(ns, namespace)
+		ns[agentTypeNSPartNumber].Value = agentType
+
+		metrics = append(metrics, plugin.MetricType{
+			Timestamp_: time.Now(),
+			Namespace_: ns,
+			Data_:      counts[agentType],
+			Tags_:      map[string]string{"agent_type": agentTypes[agentType]},
+		})
+	}
+	return metrics
+}
+
+//getAgentTypeElementValue returns value of agent type namespace element, e.g. "L3 agent" is reported as "l3_agent"
+func getAgentTypeElementValue(agentType string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
 		}
-		done.Done()
-	}()
+		return '_'
+	}, agentType)
+}
 
-	var tenantQuotasList map[string]map[string]int64
-	go func() {
-		var serr serror.SnapError
-		tenantQuotasList, serr = openstackintel.GetQuotasPerTenant(networkClient, tenantList)
-		if serr != nil {
-			log.WithFields(serr.Fields()).Warn(serr.Error())
-			panic(err)
+//boolToInt64 converts flag to metric value, 1 for true and 0 for false
+func boolToInt64(flag bool) int64 {
+	if flag {
+		return 1
+	}
+	return 0
+}
+
+//isResourceNamespace checks whether namespace is a namespace of metric of single network, subnet, router or subnet pool
+func isResourceNamespace(namespace core.Namespace) bool {
+	switch len(namespace) {
+	case resourceNSLength:
+		switch namespace[resourceNSPartNumber].Value {
+		case networkNSPart, routerNSPart, subnetPoolsNSPart:
+			return true
+		default:
+			return false
 		}
-		done.Done()
-	}()
+	case subnetNSLength:
+		return namespace[resourceNSPartNumber].Value == networkNSPart && namespace[subnetsNSPartNumber].Value == subnetsNSPart
+	default:
+		return false
+	}
+}
 
-	done.Wait()
+//getResourceMetricFamilies returns names of resource families required by metric of single network, subnet, router or subnet pool, empty slice if metric does not exist
+func getResourceMetricFamilies(namespace core.Namespace) []string {
+	metricName := namespace[len(namespace)-1].Value
+	if isIPAvailabilityMetric(metricName) {
+		if len(namespace) == subnetNSLength || namespace[resourceNSPartNumber].Value == networkNSPart {
+			return []string{ipAvailabilityFamily}
+		}
+		return []string{}
+	}
+	if len(namespace) != resourceNSLength {
+		return []string{}
+	}
 
+	switch namespace[resourceNSPartNumber].Value + "/" + metricName {
+	case networkNSPart + "/" + portsCountMetric:
+		return []string{networksFamily, portsFamily}
+	case networkNSPart + "/" + subnetsCountMetric:
+		return []string{networksFamily, subnetsFamily}
+	case routerNSPart + "/" + interfacesCountMetric:
+		return []string{routersFamily, portsFamily}
+	case subnetPoolsNSPart + "/" + prefixCapacityMetric:
+		return []string{subnetPoolsFamily}
+	case subnetPoolsNSPart + "/" + prefixFreeMetric, subnetPoolsNSPart + "/" + prefixUtilizationMetric:
+		// address space allocated from pools is calculated while subnets are counted
+		return []string{subnetPoolsFamily, subnetsFamily}
+	default:
+		return []string{}
+	}
+}
+
+//resourceInfo identifies single network, router or subnet pool and describes it by tags
+type resourceInfo struct {
+	id       string
+	tenantID string
+	tags     map[string]string
+}
+
+//getNetworkInfos returns networks sorted by ID, tagged with name, shared and external flags
+func getNetworkInfos(networks map[string]tenantresources.Network) []resourceInfo {
+	infos := []resourceInfo{}
+	for _, network := range networks {
+		infos = append(infos, resourceInfo{
+			id:       network.ID,
+			tenantID: network.TenantID,
+			tags: map[string]string{
+				"network_name": network.Name,
+				"shared":       strconv.FormatBool(network.Shared),
+				"external":     strconv.FormatBool(network.External),
+			},
+		})
+	}
+	sort.Sort(byID(infos))
+	return infos
+}
+
+//getRouterInfos returns routers sorted by ID, tagged with name and external flag (router has external gateway)
+func getRouterInfos(routers map[string]tenantresources.Router) []resourceInfo {
+	infos := []resourceInfo{}
+	for _, router := range routers {
+		infos = append(infos, resourceInfo{
+			id:       router.ID,
+			tenantID: router.TenantID,
+			tags: map[string]string{
+				"router_name": router.Name,
+				"external":    strconv.FormatBool(len(router.ExternalGatewayInfo) > 0),
+			},
+		})
+	}
+	sort.Sort(byID(infos))
+	return infos
+}
+
+//getSubnetPoolInfos returns subnet pools sorted by ID, tagged with name, IP version and shared flag
+func getSubnetPoolInfos(subnetPools map[string]openstackintel.SubnetPoolUsage) []resourceInfo {
+	infos := []resourceInfo{}
+	for _, subnetPool := range subnetPools {
+		infos = append(infos, resourceInfo{
+			id:       subnetPool.ID,
+			tenantID: subnetPool.TenantID,
+			tags: map[string]string{
+				"subnetpool_name": subnetPool.Name,
+				"ip_version":      strconv.Itoa(subnetPool.IPVersion),
+				"shared":          strconv.FormatBool(subnetPool.Shared),
+			},
+		})
+	}
+	sort.Sort(byID(infos))
+	return infos
+}
+
+//byID sorts resources by ID
+type byID []resourceInfo
+
+func (r byID) Len() int           { return len(r) }
+func (r byID) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r byID) Less(i, j int) bool { return r[i].id < r[j].id }
+
+//getResourceMetrics returns metrics of networks, routers or subnet pools of tenants matching namespace, resource element can be a wildcard
+func getResourceMetrics(namespace core.Namespace, tenantList []types.Tenant, tenantNamespace string, resources []resourceInfo, value func(id string) interface{}) []plugin.MetricType {
+	resourceElement := namespace[resourceIDNSPartNumber].Value
 	metrics := []plugin.MetricType{}
-	for _, metricType := range metricTypes {
+	for _, tenant := range resolveTenants(tenantList, namespace[tenantNameNSPartNumber].Value, tenantNamespace) {
+		for _, resource := range resources {
+			if resource.tenantID != tenant.ID || (resourceElement != tenantWildcard && resourceElement != resource.id) {
+				continue
+			}
 
-		namespace := metricType.Namespace()
-		if len(namespace) != nsLength {
-			f := map[string]interface{}{"namespace": metricType.Namespace().String()}
-			serr := serror.New(fmt.Errorf("Incorrect namespace length"), f)
-			log.WithFields(serr.Fields()).Warn(serr.String())
-			continue
+			tags := getTenantTags(tenant)
+			for k, v := range resource.tags {
+				tags[k] = v
+			}
+			ns := make(core.Namespace, len(namespace))
+			copy(ns, namespace)
+			ns[tenantNameNSPartNumber].Value = getTenantElementValue(tenant, tenantNamespace)
+			ns[resourceIDNSPartNumber].Value = resource.id
+
+			metrics = append(metrics, plugin.MetricType{
+				Timestamp_: time.Now(),
+				Namespace_: ns,
+				Data_:      value(resource.id),
+				Tags_:      tags,
+			})
 		}
+	}
+	return metrics
+}
 
-		metric := plugin.MetricType{
-			Timestamp_: time.Now(),
-			Namespace_: namespace,
-		}
-
-		tenantName := namespace[tenantNameNSPartNumber].Value
-		var val int64
-		var ok bool
-		switch namespace[metricNameNSPartNumber].Value {
-		case networksCountMetric:
-			val, ok = tenantNetworks[tenantName]
-		case subnetsCountMetric:
-			val, ok = tenantSubnets[tenantName]
-		case routersCountMetric:
-			val, ok = tenantRouters[tenantName]
-		case portsCountMetric:
-			val, ok = tenantPorts[tenantName]
-		case floatingipsCountMetric:
-			val, ok = tenantFloatingips[tenantName]
+//getCountValues returns function which gives number of resources counted for resource with given ID
+func getCountValues(counts map[string]int64) func(id string) interface{} {
+	return func(id string) interface{} {
+		return counts[id]
+	}
+}
+
+//getSubnetPoolValues returns function which gives value of metric of subnet pool with given ID
+func getSubnetPoolValues(metricName string, subnetPools map[string]openstackintel.SubnetPoolUsage, used map[string]float64) func(id string) interface{} {
+	return func(id string) interface{} {
+		subnetPool := subnetPools[id]
+		switch metricName {
+		case prefixCapacityMetric:
+			return capToInt64(subnetPool.Capacity)
+		case prefixFreeMetric:
+			return capToInt64(math.Max(subnetPool.Capacity-used[id], 0))
 		default:
+			if subnetPool.Capacity == 0 {
+				return float64(0)
+			}
+			return used[id] / subnetPool.Capacity * 100
+		}
+	}
+}
 
-			if !strings.HasPrefix(namespace[metricNameNSPartNumber].Value, quotas) {
-				f := map[string]interface{}{"namespace": "/" + metricType.Namespace().String()}
-				serr := serror.New(fmt.Errorf("Incorrect namespace, prefix '%s' is desired", quotas), f)
-				log.WithFields(serr.Fields()).Warn(serr.String())
+//isSubnetPoolMetric checks whether metric describes address space of single subnet pool
+func isSubnetPoolMetric(metricName string) bool {
+	for _, name := range subnetPoolMetrics {
+		if name == metricName {
+			return true
+		}
+	}
+	return false
+}
+
+//getIPAvailabilityMetrics returns IP availability metrics of networks (or subnets) matching namespace, network and subnet elements can be wildcards
+func getIPAvailabilityMetrics(namespace core.Namespace, tenantList []types.Tenant, tenantNamespace string, availabilities []ipavailability.NetworkIPAvailability) []plugin.MetricType {
+	metricName := namespace[len(namespace)-1].Value
+	networkElement := namespace[resourceIDNSPartNumber].Value
+	metrics := []plugin.MetricType{}
+	for _, tenant := range resolveTenants(tenantList, namespace[tenantNameNSPartNumber].Value, tenantNamespace) {
+		for _, network := range availabilities {
+			if network.TenantID != tenant.ID || (networkElement != tenantWildcard && networkElement != network.NetworkID) {
+				continue
+			}
+
+			tags := getTenantTags(tenant)
+			tags["network_name"] = network.NetworkName
+			ns := make(core.Namespace, len(namespace))
+			copy(ns, namespace)
+			ns[tenantNameNSPartNumber].Value = getTenantElementValue(tenant, tenantNamespace)
+			ns[resourceIDNSPartNumber].Value = network.NetworkID
+
+			if len(namespace) == resourceNSLength {
+				metrics = append(metrics, plugin.MetricType{
+					Timestamp_: time.Now(),
+					Namespace_: ns,
+					Data_:      getIPAvailabilityValue(metricName, network.TotalIPs, network.UsedIPs),
+					Tags_:      tags,
+				})
 				continue
 			}
 
-			quotaName := namespace[metricNameNSPartNumber].Value[len(quotas):]
-			val, ok = tenantQuotasList[tenantName][quotaName]
+			subnetElement := namespace[subnetIDNSPartNumber].Value
+			for _, subnet := range network.Subnets {
+				if subnetElement != tenantWildcard && subnetElement != subnet.SubnetID {
+					continue
+				}
+
+				subnetTags := map[string]string{"subnet_name": subnet.SubnetName, "cidr": subnet.CIDR}
+				for k, v := range tags {
+					subnetTags[k] = v
+				}
+				subnetNs := make(core.Namespace, len(ns))
+				copy(subnetNs, ns)
+				subnetNs[subnetIDNSPartNumber].Value = subnet.SubnetID
+
+				metrics = append(metrics, plugin.MetricType{
+					Timestamp_: time.Now(),
+					Namespace_: subnetNs,
+					Data_:      getIPAvailabilityValue(metricName, subnet.TotalIPs, subnet.UsedIPs),
+					Tags_:      subnetTags,
+				})
+			}
+		}
+	}
+	return metrics
+}
+
+//isIPAvailabilityMetric checks whether metric with given name indicates IP address availability
+func isIPAvailabilityMetric(metricName string) bool {
+	for _, m := range ipAvailabilityMetrics {
+		if m == metricName {
+			return true
 		}
+	}
+	return false
+}
 
-		if !ok {
-			f := map[string]interface{}{"namespace": metricType.Namespace().String(), "tenantName": tenantName}
-			serr := serror.New(fmt.Errorf("Incorrect namespace, metric with specified namespace does not exist"), f)
-			log.WithFields(serr.Fields()).Warn(serr.String())
+//getIPAvailabilityValue returns value of IP availability metric, numbers of addresses which exceed int64 (large IPv6 subnets) are capped
+func getIPAvailabilityValue(metricName string, total, used float64) interface{} {
+	switch metricName {
+	case ipTotalMetric:
+		return capToInt64(total)
+	case ipUsedMetric:
+		return capToInt64(used)
+	default:
+		if total <= 0 {
+			return float64(0)
+		}
+		return used / total * 100
+	}
+}
+
+//capToInt64 converts number to int64, numbers which do not fit are capped to maximum int64 value
+func capToInt64(value float64) int64 {
+	if value >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(value)
+}
+
+//getTenantNamespace returns the way tenants are identified in namespace (by name or by ID) based on configuration
+func getTenantNamespace(cfg interface{}) (string, serror.SnapError) {
+	tenantNamespace := getConfigString(cfg, cfgTenantNamespace)
+	switch tenantNamespace {
+	case "":
+		return tenantNamespaceName, nil
+	case tenantNamespaceName, tenantNamespaceID:
+		return tenantNamespace, nil
+	default:
+		f := map[string]interface{}{cfgTenantNamespace: tenantNamespace}
+		serr := redact.New(fmt.Errorf("Incorrect configuration, %s has to be '%s' or '%s'", cfgTenantNamespace, tenantNamespaceName, tenantNamespaceID), f)
+		log.WithFields(serr.Fields()).Warn(serr.Error())
+		return "", serr
+	}
+}
+
+//tenantFilter selects monitored tenants, nil expression matches every tenant
+type tenantFilter struct {
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+	domain  *regexp.Regexp
+}
+
+//getTenantFilter creates filter of tenants based on configuration
+func getTenantFilter(cfg interface{}) (tenantFilter, serror.SnapError) {
+	filter := tenantFilter{}
+	for name, expr := range map[string]**regexp.Regexp{
+		cfgTenantInclude: &filter.include,
+		cfgTenantExclude: &filter.exclude,
+		cfgDomainFilter:  &filter.domain,
+	} {
+		value := getConfigString(cfg, name)
+		if value == "" {
 			continue
 		}
-		metric.Data_ = val
-		metrics = append(metrics, metric)
+
+		// expression has to match whole name, not only its part
+		re, err := regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			f := map[string]interface{}{name: value}
+			serr := redact.New(fmt.Errorf("Incorrect configuration, %s is not a valid regular expression: %v", name, err), f)
+			log.WithFields(serr.Fields()).Warn(serr.Error())
+			return tenantFilter{}, serr
+		}
+		*expr = re
 	}
-	return metrics, nil
+	return filter, nil
 }
 
-// GetConfigPolicy returns config policy
-// It returns error in case retrieval was not successful
-func (c *Collector) GetConfigPolicy() (*cpolicy.ConfigPolicy, error) {
-	cp := cpolicy.New()
-	config := cpolicy.NewPolicyNode()
+//matches checks whether tenant is selected by filter, domain filter is not applied to tenants without domain (Identity API v2)
+func (f tenantFilter) matches(tenant types.Tenant) bool {
+	if f.include != nil && !f.include.MatchString(tenant.Name) {
+		return false
+	}
+	if f.exclude != nil && f.exclude.MatchString(tenant.Name) {
+		return false
+	}
+	if f.domain != nil && tenant.DomainID != "" && !f.domain.MatchString(tenant.DomainID) {
+		return false
+	}
+	return true
+}
 
-	r1, err := cpolicy.NewStringRule(cfgURL, true)
-	if err != nil {
-		return cp, err
+//apply returns tenants selected by filter
+func (f tenantFilter) apply(tenantList []types.Tenant) []types.Tenant {
+	filtered := []types.Tenant{}
+	for _, tenant := range tenantList {
+		if f.matches(tenant) {
+			filtered = append(filtered, tenant)
+		}
 	}
-	r1.Description = "URL for OpenStack Identity endpoint"
-	config.Add(r1)
+	return filtered
+}
 
-	r2, err := cpolicy.NewStringRule(cfgUser, true)
-	if err != nil {
-		return cp, err
+//skipReservedTenants returns tenants which namespace element does not collide with static elements replacing tenant (_all, _errors, _agents)
+//Skipped tenants are logged, their metrics could not be told apart from aggregate, error and agent metrics
+func skipReservedTenants(tenantList []types.Tenant, tenantNamespace string) []types.Tenant {
+	selected := []types.Tenant{}
+	for _, tenant := range tenantList {
+		switch getTenantElementValue(tenant, tenantNamespace) {
+		case aggregateNSPart, errorsNSPart, agentsNSPart:
+			log.WithFields(log.Fields{tenantIDElement: tenant.ID, tenantNameElement: tenant.Name}).Warn("Tenant skipped, its namespace element is reserved")
+		default:
+			selected = append(selected, tenant)
+		}
 	}
-	r2.Description = "user name used to authenticate"
-	config.Add(r2)
+	return selected
+}
 
-	r3, err := cpolicy.NewStringRule(cfgPassword, true)
-	if err != nil {
-		return cp, err
+//getTenantElementValue returns value of tenant namespace element for given tenant
+func getTenantElementValue(tenant types.Tenant, tenantNamespace string) string {
+	if tenantNamespace == tenantNamespaceID {
+		return tenant.ID
 	}
-	r3.Description = "password used to authenticate"
-	config.Add(r3)
+	return tenant.Name
+}
 
-	r4, err := cpolicy.NewStringRule(cfgTenant, true)
-	if err != nil {
-		return cp, err
+//getTenantTags returns tags which identify tenant regardless of the way tenants are identified in namespace
+func getTenantTags(tenant types.Tenant) map[string]string {
+	tags := map[string]string{
+		tenantIDElement:   tenant.ID,
+		tenantNameElement: tenant.Name,
 	}
-	r4.Description = " tenant name used to authenticate"
-	config.Add(r4)
+	if tenant.DomainID != "" {
+		tags["domain_id"] = tenant.DomainID
+	}
+	return tags
+}
 
-	cp.Add([]string{""}, config)
-	return cp, nil
+//getQuotaValue returns quota limit or, if quota details are available, number of used or reserved resources
+func getQuotaValue(tenantQuotasList map[string]map[string]int64, tenantQuotaDetails map[string]map[string]tenantquotas.QuotaDetails, tenantID, name string) (int64, bool) {
+	if limit, ok := tenantQuotasList[tenantID][name]; ok {
+		return limit, true
+	}
+	if strings.HasSuffix(name, quotaUsedSuffix) {
+		details, ok := tenantQuotaDetails[tenantID][strings.TrimSuffix(name, quotaUsedSuffix)]
+		return details.Used, ok
+	}
+	if strings.HasSuffix(name, quotaReservedSuffix) {
+		details, ok := tenantQuotaDetails[tenantID][strings.TrimSuffix(name, quotaReservedSuffix)]
+		return details.Reserved, ok
+	}
+	return 0, false
+}
+
+//getQuotaUsage returns number of used resources and quota limit for given tenant and quota name
+func getQuotaUsage(tenantCounts map[string]map[string]int64, tenantQuotasList map[string]map[string]int64, tenantID, quotaName string) (int64, int64, bool) {
+	countMetric, ok := quotaUsageMetrics[quotaName]
+	if !ok {
+		return 0, 0, false
+	}
+	used, ok := tenantCounts[countMetric][tenantID]
+	if !ok {
+		return 0, 0, false
+	}
+	limit, ok := tenantQuotasList[tenantID][quotaName]
+	if !ok {
+		return 0, 0, false
+	}
+	return used, limit, true
+}
+
+//quotaUtilization returns ratio of used resources to limit, unlimited quota gives -1
+func quotaUtilization(used, limit int64) float64 {
+	if limit == unlimitedQuota {
+		return unlimitedQuota
+	}
+	if limit == 0 {
+		if used == 0 {
+			return 0
+		}
+		return 1
+	}
+	return float64(used) / float64(limit)
+}
+
+//quotaHeadroom returns number of resources which still can be created, unlimited quota gives -1
+func quotaHeadroom(used, limit int64) int64 {
+	if limit == unlimitedQuota {
+		return unlimitedQuota
+	}
+	if used > limit {
+		return 0
+	}
+	return limit - used
 }
 
 func getInfoFields(metric string) infoFields {
+	if m, ok := lbaasMetrics[metric]; ok {
+		return infoFields{description: m.getDescription(), unit: ""}
+	}
 	info, ok := neutronInfoFields[metric]
 	if !ok {
 		info = infoFields{description: "", unit: ""}
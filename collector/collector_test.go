@@ -22,16 +22,27 @@ limitations under the License.
 package collector
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	log "github.com/Sirupsen/logrus"
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/redact"
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/types"
 	"github.com/intelsdi-x/snap-plugin-utilities/str"
 	"github.com/intelsdi-x/snap/control/plugin"
 	"github.com/intelsdi-x/snap/control/plugin/cpolicy"
 	"github.com/intelsdi-x/snap/core"
 	"github.com/intelsdi-x/snap/core/cdata"
 	"github.com/intelsdi-x/snap/core/ctypes"
+	"github.com/intelsdi-x/snap/core/serror"
 	th "github.com/rackspace/gophercloud/testhelper"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/suite"
@@ -41,10 +52,43 @@ type TestSuite struct {
 	suite.Suite
 	Token                  string
 	NetworkServiceEndpoint string
+	OctaviaEndpoint        string
+	FloatingIPsUnavailable bool
+	IPAvailabilityStatus   int
+	UnavailableExtensions  map[string]bool
+	FailingExtensions      map[string]bool
+	Requests               *requestCounter
+}
+
+//requestCounter counts requests sent to mocked OpenStack endpoints per URL path
+type requestCounter struct {
+	sync.Mutex
+	counts map[string]int
+}
+
+func (c *requestCounter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.Lock()
+	c.counts[r.URL.Path]++
+	c.Unlock()
+	th.Mux.ServeHTTP(w, r)
+}
+
+func (c *requestCounter) reset() {
+	c.Lock()
+	defer c.Unlock()
+	c.counts = map[string]int{}
+}
+
+func (c *requestCounter) count(path string) int {
+	c.Lock()
+	defer c.Unlock()
+	return c.counts[path]
 }
 
 func (s *TestSuite) SetupSuite() {
 	th.SetupHTTP()
+	s.Requests = &requestCounter{counts: map[string]int{}}
+	th.Server.Config.Handler = s.Requests
 	registerRoot()
 	registerAuthentication(s)
 	registerEndpoints(s)
@@ -54,7 +98,19 @@ func (s *TestSuite) SetupSuite() {
 	registerRouters(s)
 	registerPorts(s)
 	registerFloatingIPs(s)
+	registerSecurityGroups(s)
+	registerSecurityGroupRules(s)
 	registerQuotas(s)
+	registerQuotaDetails(s)
+	registerIPAvailabilities(s)
+	registerAgents(s)
+	registerLoadBalancers(s)
+	registerVPN(s)
+	registerRBACPoliciesAndSubnetPools(s)
+	registerQoSPolicies(s)
+	registerFWaaS(s)
+	registerExtensions(s)
+	registerLoadBalancerQuotas(s)
 }
 
 func (s *TestSuite) TearDownSuite() {
@@ -71,77 +127,109 @@ func (s *TestSuite) TestGetMetricTypes() {
 		cfg := setupCfg(th.Endpoint(), "me", "secret", "admin")
 		collector := New()
 		So(collector, ShouldNotBeNil)
+		s.Requests.reset()
 		mts, err := collector.GetMetricTypes(cfg)
 
 		Convey("Then no error should be reported", func() {
 			So(err, ShouldBeNil)
 		})
 
+		Convey("and neither tenants nor quotas are retrieved", func() {
+			So(s.Requests.count("/v2.0/tenants"), ShouldEqual, 0)
+			So(s.Requests.count("/v2.0/quotas/222222/details"), ShouldEqual, 0)
+			So(s.Requests.count("/v2.0/quotas/111111/details"), ShouldEqual, 0)
+		})
+
 		Convey("and proper metric types are returned", func() {
 			metricNames := []string{}
 			for _, m := range mts {
 				metricNames = append(metricNames, m.Namespace().String())
 			}
 
-			So(len(mts), ShouldEqual, 28)
+			So(len(mts), ShouldEqual, 442)
 
-			ns := core.NewNamespace(vendor, openstack, pluginName, "admin", networksCountMetric)
+			ns := tenantNamespace(networksCountMetric)
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "admin", subnetsCountMetric)
+			ns = tenantNamespace(subnetsCountMetric)
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "admin", routersCountMetric)
+			ns = tenantNamespace(routersCountMetric)
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "admin", portsCountMetric)
+			ns = tenantNamespace(portsCountMetric)
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "admin", floatingipsCountMetric)
+			ns = tenantNamespace(floatingipsCountMetric)
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "admin", quotas+"subnet")
+			ns = tenantNamespace(securityGroupsCountMetric)
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "admin", quotas+"network")
+			ns = tenantNamespace(securityGroupRulesCountMetric)
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "admin", quotas+"floatingip")
+			ns = tenantNamespace(quotas + "subnet")
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "admin", quotas+"subnetpool")
+			ns = tenantNamespace(quotas + "network")
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "admin", quotas+"security_group_rule")
+			ns = tenantNamespace(quotas + "floatingip")
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "admin", quotas+"security_group")
+			ns = tenantNamespace(quotas + "subnetpool")
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "admin", quotas+"router")
+			ns = tenantNamespace(quotas + "security_group_rule")
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "admin", quotas+"rbac_policy")
+			ns = tenantNamespace(quotas + "security_group")
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "admin", quotas+"port")
+			ns = tenantNamespace(quotas + "router")
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-
-			ns = core.NewNamespace(vendor, openstack, pluginName, "demo", networksCountMetric)
+			ns = tenantNamespace(quotas + "rbac_policy")
+			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
+			ns = tenantNamespace(quotas + "port")
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "demo", subnetsCountMetric)
+			ns = tenantNamespace(utilization + "network")
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "demo", routersCountMetric)
+			ns = tenantNamespace(headroom + "network")
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "demo", portsCountMetric)
+			ns = tenantNamespace(utilization + "rbac_policy")
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "demo", floatingipsCountMetric)
+			ns = tenantNamespace(quotas + "port" + quotaUsedSuffix)
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "demo", quotas+"subnet")
+			ns = tenantNamespace(quotas + "port" + quotaReservedSuffix)
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "demo", quotas+"network")
+
+			ns = core.NewNamespace(vendor, openstack, pluginName, errorsNSPart, quotasFamily)
+			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
+			ns = core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, networksCountMetric)
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "demo", quotas+"floatingip")
+			ns = core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, quotas+"port")
+			So(str.Contains(metricNames, ns.String()), ShouldBeFalse)
+			ns = core.NewNamespace(vendor, openstack, pluginName, errorsNSPart, agentsFamily)
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "demo", quotas+"subnetpool")
+			ns = tenantNamespace("members_operating_error_count")
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "demo", quotas+"security_group_rule")
+			ns = tenantNamespace(quotas + "loadbalancer")
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "demo", quotas+"security_group")
+			ns = tenantNamespace(utilization + "loadbalancer")
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "demo", quotas+"router")
+			ns = tenantNamespace(ipsecSiteConnectionsDownCountMetric)
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "demo", quotas+"rbac_policy")
+			ns = tenantNamespace(headroom + "ipsec_site_connection")
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
-			ns = core.NewNamespace(vendor, openstack, pluginName, "demo", quotas+"port")
+			ns = core.NewNamespace(vendor, openstack, pluginName, agentsNSPart, "*", "*", agentAliveMetric)
 			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
+			ns = core.NewNamespace(vendor, openstack, pluginName, agentsNSPart, "*", agentsDownCountMetric)
+			So(str.Contains(metricNames, ns.String()), ShouldBeTrue)
+		})
+
+		Convey("and every metric type is described", func() {
+			for _, m := range mts {
+				So(m.Description(), ShouldNotBeEmpty)
+			}
+		})
+
+		Convey("and tenant name is a dynamic element of namespace", func() {
+			for _, m := range mts {
+				tenantElement := m.Namespace()[tenantNameNSPartNumber]
+				if tenantElement.Value == errorsNSPart || tenantElement.Value == aggregateNSPart || tenantElement.Value == agentsNSPart {
+					continue
+				}
+				So(tenantElement.IsDynamic(), ShouldBeTrue)
+				So(tenantElement.Name, ShouldEqual, tenantNameElement)
+			}
 		})
 	})
 }
@@ -180,6 +268,19 @@ func (s *TestSuite) TestCollectMetrics() {
 		ns27 := core.NewNamespace(vendor, openstack, pluginName, "demo", quotas+"rbac_policy")
 		ns28 := core.NewNamespace(vendor, openstack, pluginName, "demo", quotas+"port")
 
+		ns29 := core.NewNamespace(vendor, openstack, pluginName, "admin", securityGroupsCountMetric)
+		ns30 := core.NewNamespace(vendor, openstack, pluginName, "admin", securityGroupRulesCountMetric)
+		ns31 := core.NewNamespace(vendor, openstack, pluginName, "demo", securityGroupsCountMetric)
+		ns32 := core.NewNamespace(vendor, openstack, pluginName, "demo", securityGroupRulesCountMetric)
+
+		ns33 := core.NewNamespace(vendor, openstack, pluginName, "admin", utilization+"network")
+		ns34 := core.NewNamespace(vendor, openstack, pluginName, "admin", headroom+"network")
+		ns35 := core.NewNamespace(vendor, openstack, pluginName, "demo", utilization+"port")
+		ns36 := core.NewNamespace(vendor, openstack, pluginName, "demo", headroom+"port")
+
+		ns37 := core.NewNamespace(vendor, openstack, pluginName, "admin", quotas+"port"+quotaUsedSuffix)
+		ns38 := core.NewNamespace(vendor, openstack, pluginName, "admin", quotas+"port"+quotaReservedSuffix)
+
 		mTypes := []plugin.MetricType{
 			plugin.MetricType{Namespace_: ns1, Config_: cfg.ConfigDataNode},
 			plugin.MetricType{Namespace_: ns2, Config_: cfg.ConfigDataNode},
@@ -211,6 +312,16 @@ func (s *TestSuite) TestCollectMetrics() {
 			plugin.MetricType{Namespace_: ns26, Config_: cfg.ConfigDataNode},
 			plugin.MetricType{Namespace_: ns27, Config_: cfg.ConfigDataNode},
 			plugin.MetricType{Namespace_: ns28, Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: ns29, Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: ns30, Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: ns31, Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: ns32, Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: ns33, Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: ns34, Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: ns35, Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: ns36, Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: ns37, Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: ns38, Config_: cfg.ConfigDataNode},
 		}
 
 		Convey("When ColelctMetrics() is called", func() {
@@ -235,7 +346,7 @@ func (s *TestSuite) TestCollectMetrics() {
 					metricNames[ns] = m.Data()
 				}
 
-				So(len(mts), ShouldEqual, 28)
+				So(len(mts), ShouldEqual, 38)
 
 				//networks_count
 				val, ok := metricNames[ns1.String()]
@@ -418,6 +529,74 @@ func (s *TestSuite) TestCollectMetrics() {
 				v, ok = val.(int64)
 				So(ok, ShouldBeTrue)
 				So(v, ShouldEqual, 51)
+
+				//security_groups_count
+				val, ok = metricNames[ns29.String()]
+				So(ok, ShouldBeTrue)
+				v, ok = val.(int64)
+				So(ok, ShouldBeTrue)
+				So(v, ShouldEqual, 2)
+
+				val, ok = metricNames[ns31.String()]
+				So(ok, ShouldBeTrue)
+				v, ok = val.(int64)
+				So(ok, ShouldBeTrue)
+				So(v, ShouldEqual, 1)
+
+				//security_group_rules_count
+				val, ok = metricNames[ns30.String()]
+				So(ok, ShouldBeTrue)
+				v, ok = val.(int64)
+				So(ok, ShouldBeTrue)
+				So(v, ShouldEqual, 3)
+
+				val, ok = metricNames[ns32.String()]
+				So(ok, ShouldBeTrue)
+				v, ok = val.(int64)
+				So(ok, ShouldBeTrue)
+				So(v, ShouldEqual, 1)
+
+				//utilization_network
+				val, ok = metricNames[ns33.String()]
+				So(ok, ShouldBeTrue)
+				f, ok := val.(float64)
+				So(ok, ShouldBeTrue)
+				So(f, ShouldAlmostEqual, 2.0/13.0)
+
+				//headroom_network
+				val, ok = metricNames[ns34.String()]
+				So(ok, ShouldBeTrue)
+				v, ok = val.(int64)
+				So(ok, ShouldBeTrue)
+				So(v, ShouldEqual, 11)
+
+				//utilization_port
+				val, ok = metricNames[ns35.String()]
+				So(ok, ShouldBeTrue)
+				f, ok = val.(float64)
+				So(ok, ShouldBeTrue)
+				So(f, ShouldEqual, 0)
+
+				//headroom_port
+				val, ok = metricNames[ns36.String()]
+				So(ok, ShouldBeTrue)
+				v, ok = val.(int64)
+				So(ok, ShouldBeTrue)
+				So(v, ShouldEqual, 51)
+
+				//quotas_port_used
+				val, ok = metricNames[ns37.String()]
+				So(ok, ShouldBeTrue)
+				v, ok = val.(int64)
+				So(ok, ShouldBeTrue)
+				So(v, ShouldEqual, 3)
+
+				//quotas_port_reserved
+				val, ok = metricNames[ns38.String()]
+				So(ok, ShouldBeTrue)
+				v, ok = val.(int64)
+				So(ok, ShouldBeTrue)
+				So(v, ShouldEqual, 1)
 			})
 		})
 	})
@@ -455,328 +634,1803 @@ func (s *TestSuite) TestCollectMetrics() {
 	})
 }
 
-func (s *TestSuite) TestGetConfigPolicy() {
-	Convey("Meta should return metadata for the plugin", s.T(), func() {
-		meta := Meta()
-		So(meta.Name, ShouldResemble, pluginName)
-		So(meta.Version, ShouldResemble, version)
-		So(meta.Type, ShouldResemble, plugin.CollectorPluginType)
-	})
+func (s *TestSuite) TestCollectMetricsDynamicTenant() {
+	Convey("Given metric types with tenant name resolved at collect time", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
 
-	Convey("Given config with enpoint, user and password defined", s.T(), func() {
-		collector := New()
+		mTypes := []plugin.MetricType{
+			plugin.MetricType{Namespace_: tenantNamespace(networksCountMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: tenantNamespace(quotas + "port"), Config_: cfg.ConfigDataNode},
+		}
 
-		Convey("So collector should not be nil", func() {
-			So(collector, ShouldNotBeNil)
-		})
+		Convey("When CollectMetrics() is called", func() {
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
 
-		configPolicy, err := collector.GetConfigPolicy()
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
 
-		Convey("collector.GetConfigPolicy() should return a config policy", func() {
-			Convey("So config policy should not be nil", func() {
-				So(configPolicy, ShouldNotBeNil)
+			Convey("Then metrics are returned for every tenant", func() {
+				So(len(mts), ShouldEqual, 4)
+
+				metrics := map[string]interface{}{}
+				for _, m := range mts {
+					metrics[m.Namespace().String()] = m.Data()
+				}
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, "admin", networksCountMetric).String()], ShouldEqual, 2)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, "demo", networksCountMetric).String()], ShouldEqual, 1)
+				So(metrics, ShouldContainKey, core.NewNamespace(vendor, openstack, pluginName, "admin", quotas+"port").String())
+				So(metrics, ShouldContainKey, core.NewNamespace(vendor, openstack, pluginName, "demo", quotas+"port").String())
 			})
 
-			Convey("So we should not get an err retreiving the config policy", func() {
+			Convey("Then requested namespaces are not modified", func() {
+				So(mTypes[0].Namespace()[tenantNameNSPartNumber].Value, ShouldEqual, tenantWildcard)
+				So(mTypes[1].Namespace()[tenantNameNSPartNumber].Value, ShouldEqual, tenantWildcard)
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestTenantIDNamespace() {
+	Convey("Given config with tenants identified in namespace by ID", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+		cfg.AddItem(cfgTenantNamespace, ctypes.ConfigValueStr{Value: tenantNamespaceID})
+
+		Convey("When GetMetricTypes() is called", func() {
+			collector := New()
+			mts, err := collector.GetMetricTypes(cfg)
+
+			Convey("Then tenant ID is a dynamic element of namespace", func() {
 				So(err, ShouldBeNil)
+				for _, m := range mts {
+					tenantElement := m.Namespace()[tenantNameNSPartNumber]
+					if tenantElement.Value == errorsNSPart || tenantElement.Value == aggregateNSPart || tenantElement.Value == agentsNSPart {
+						continue
+					}
+					So(tenantElement.Name, ShouldEqual, tenantIDElement)
+				}
 			})
+		})
 
-			Convey("So config policy should be a cpolicy.ConfigPolicy", func() {
-				So(configPolicy, ShouldHaveSameTypeAs, &cpolicy.ConfigPolicy{})
+		Convey("When CollectMetrics() is called", func() {
+			mTypes := []plugin.MetricType{
+				plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "222222", networksCountMetric), Config_: cfg.ConfigDataNode},
+				plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "111111", quotas+"port"), Config_: cfg.ConfigDataNode},
+				plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", networksCountMetric), Config_: cfg.ConfigDataNode},
+			}
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then metrics are returned for tenants matched by ID", func() {
+				So(err, ShouldBeNil)
+				So(len(mts), ShouldEqual, 2)
+
+				metrics := map[string]plugin.MetricType{}
+				for _, m := range mts {
+					metrics[m.Namespace().String()] = m
+				}
+
+				m := metrics[core.NewNamespace(vendor, openstack, pluginName, "222222", networksCountMetric).String()]
+				So(m.Data(), ShouldEqual, 2)
+				So(m.Tags()[tenantIDElement], ShouldEqual, "222222")
+				So(m.Tags()[tenantNameElement], ShouldEqual, "admin")
+
+				m = metrics[core.NewNamespace(vendor, openstack, pluginName, "111111", quotas+"port").String()]
+				So(m.Tags()[tenantNameElement], ShouldEqual, "demo")
 			})
+		})
+	})
 
-			correctConfig := make(map[string]ctypes.ConfigValue)
-			correctConfig[cfgURL] = ctypes.ConfigValueStr{Value: th.Endpoint()}
-			correctConfig[cfgUser] = ctypes.ConfigValueStr{Value: "admin"}
-			correctConfig[cfgPassword] = ctypes.ConfigValueStr{Value: "pass"}
-			correctConfig[cfgTenant] = ctypes.ConfigValueStr{Value: "admin"}
+	Convey("Given config with incorrect way of identifying tenants", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+		cfg.AddItem(cfgTenantNamespace, ctypes.ConfigValueStr{Value: "uuid"})
 
-			cfg, errs := configPolicy.Get([]string{""}).Process(correctConfig)
+		Convey("Then error is reported", func() {
+			_, serr := getTenantNamespace(cfg)
+			So(serr, ShouldNotBeNil)
 
-			Convey("So config policy should process correctConfig and return a config", func() {
-				So(cfg, ShouldNotBeNil)
+			collector := New()
+			_, err := collector.GetMetricTypes(cfg)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func (s *TestSuite) TestTenantFilter() {
+	Convey("Given tenants from different domains", s.T(), func() {
+		tenantList := []types.Tenant{
+			types.Tenant{ID: "1", Name: "prod-web", DomainID: "default"},
+			types.Tenant{ID: "2", Name: "prod-db", DomainID: "customers"},
+			types.Tenant{ID: "3", Name: "preprod-web", DomainID: "default"},
+			types.Tenant{ID: "4", Name: "service", DomainID: "default"},
+			types.Tenant{ID: "5", Name: "prod-test"},
+		}
+
+		Convey("When no filter is configured", func() {
+			filter, serr := getTenantFilter(setupCfg(th.Endpoint(), "admin", "secret", "admin"))
+
+			Convey("Then all tenants are selected", func() {
+				So(serr, ShouldBeNil)
+				So(len(filter.apply(tenantList)), ShouldEqual, 5)
 			})
+		})
 
-			Convey("So correctConfig processing should return no errors", func() {
-				So(errs.HasErrors(), ShouldBeFalse)
+		Convey("When include, exclude and domain filters are configured", func() {
+			cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+			cfg.AddItem(cfgTenantInclude, ctypes.ConfigValueStr{Value: "prod-.*"})
+			cfg.AddItem(cfgTenantExclude, ctypes.ConfigValueStr{Value: ".*-test"})
+			cfg.AddItem(cfgDomainFilter, ctypes.ConfigValueStr{Value: "default"})
+			filter, serr := getTenantFilter(cfg)
+
+			Convey("Then only tenants matching all filters are selected", func() {
+				So(serr, ShouldBeNil)
+				filtered := filter.apply(tenantList)
+				So(len(filtered), ShouldEqual, 1)
+				So(filtered[0].Name, ShouldEqual, "prod-web")
 			})
+		})
 
-			wrongConfig1 := make(map[string]ctypes.ConfigValue)
+		Convey("When domain filter is configured for tenants without domain", func() {
+			cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+			cfg.AddItem(cfgDomainFilter, ctypes.ConfigValueStr{Value: "customers"})
+			filter, serr := getTenantFilter(cfg)
+
+			Convey("Then domain filter is not applied to them", func() {
+				So(serr, ShouldBeNil)
+				filtered := filter.apply(tenantList)
+				So(len(filtered), ShouldEqual, 2)
+				So(filtered[0].Name, ShouldEqual, "prod-db")
+				So(filtered[1].Name, ShouldEqual, "prod-test")
+			})
+		})
 
-			cfg1, errs1 := configPolicy.Get([]string{""}).Process(wrongConfig1)
+		Convey("When tenants are named like static namespace elements", func() {
+			reservedList := append(tenantList,
+				types.Tenant{ID: "6", Name: aggregateNSPart},
+				types.Tenant{ID: "7", Name: errorsNSPart},
+				types.Tenant{ID: "8", Name: agentsNSPart},
+			)
+
+			Convey("Then they are skipped if tenants are identified by name", func() {
+				selected := skipReservedTenants(reservedList, tenantNamespaceName)
+				So(len(selected), ShouldEqual, 5)
+				for _, tenant := range selected {
+					So(tenant.Name, ShouldNotStartWith, "_")
+				}
+			})
 
-			Convey("So config policy should not return a config after processing wrongConfig1", func() {
-				So(cfg1, ShouldBeNil)
+			Convey("Then they are selected if tenants are identified by ID", func() {
+				So(len(skipReservedTenants(reservedList, tenantNamespaceID)), ShouldEqual, 8)
 			})
+		})
 
-			Convey("So wrongConfig1 processing should return no errors", func() {
-				So(errs1.HasErrors(), ShouldBeTrue)
+		Convey("When incorrect regular expression is configured", func() {
+			cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+			cfg.AddItem(cfgTenantExclude, ctypes.ConfigValueStr{Value: "prod-("})
+
+			Convey("Then error is reported", func() {
+				_, serr := getTenantFilter(cfg)
+				So(serr, ShouldNotBeNil)
+
+				collector := New()
+				_, err := collector.GetMetricTypes(cfg)
+				So(err, ShouldNotBeNil)
 			})
+		})
+	})
 
-			wrongConfig2 := make(map[string]ctypes.ConfigValue)
+	Convey("Given config which excludes tenant from monitoring", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+		cfg.AddItem(cfgTenantExclude, ctypes.ConfigValueStr{Value: "demo"})
 
-			cfg2, errs2 := configPolicy.Get([]string{""}).Process(wrongConfig2)
+		mTypes := []plugin.MetricType{
+			plugin.MetricType{Namespace_: tenantNamespace(quotas + "port"), Config_: cfg.ConfigDataNode},
+		}
 
-			correctConfig[cfgURL] = ctypes.ConfigValueStr{Value: th.Endpoint()}
-			correctConfig[cfgUser] = ctypes.ConfigValueStr{Value: "admin"}
+		Convey("When CollectMetrics() is called", func() {
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
 
-			Convey("So config policy should not return a config after processing wrongConfig2", func() {
-				So(cfg2, ShouldBeNil)
+			Convey("Then metrics are returned only for remaining tenants", func() {
+				So(err, ShouldBeNil)
+				So(len(mts), ShouldEqual, 1)
+				So(mts[0].Namespace()[tenantNameNSPartNumber].Value, ShouldEqual, "admin")
 			})
-			Convey("So wrongConfig2 processing should return errors", func() {
-				So(errs2.HasErrors(), ShouldBeTrue)
+
+			Convey("Then no requests are sent for excluded tenant", func() {
+				So(s.Requests.count("/v2.0/quotas/222222/details"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/quotas/111111/details"), ShouldEqual, 0)
 			})
 		})
 	})
 }
 
-func setupCfg(endpoint, user, password, tenant string) plugin.ConfigType {
-	node := cdata.NewNode()
-	node.AddItem(cfgURL, ctypes.ConfigValueStr{Value: endpoint})
-	node.AddItem(cfgUser, ctypes.ConfigValueStr{Value: user})
-	node.AddItem(cfgPassword, ctypes.ConfigValueStr{Value: password})
-	node.AddItem(cfgTenant, ctypes.ConfigValueStr{Value: tenant})
-	return plugin.ConfigType{ConfigDataNode: node}
+func (s *TestSuite) TestCollectAggregateMetrics() {
+	Convey("Given aggregate metric types", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+		cfg.AddItem(cfgTenantExclude, ctypes.ConfigValueStr{Value: "demo"})
+
+		mTypes := []plugin.MetricType{
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, networksCountMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, floatingipsCountMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, quotas+"port"), Config_: cfg.ConfigDataNode},
+		}
+
+		Convey("When CollectMetrics() is called", func() {
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then resources of all tenants, including filtered out and unknown ones, are counted", func() {
+				So(len(mts), ShouldEqual, 2)
+
+				metrics := map[string]interface{}{}
+				for _, m := range mts {
+					metrics[m.Namespace().String()] = m.Data()
+				}
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, networksCountMetric).String()], ShouldEqual, 3)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, floatingipsCountMetric).String()], ShouldEqual, 3)
+			})
+
+			Convey("Then no quotas are retrieved", func() {
+				So(s.Requests.count("/v2.0/quotas/222222/details"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/quotas/111111/details"), ShouldEqual, 0)
+			})
+		})
+	})
 }
 
-func registerRoot() {
-	th.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintf(w, `
-				{
-					"versions": {
-						"values": [
-							{
-								"status": "experimental",
-								"id": "v3.0",
-								"links": [
-									{ "href": "%s", "rel": "self" }
-								]
-							},
-							{
-								"status": "stable",
-								"id": "v2.0",
-								"links": [
-									{ "href": "%s", "rel": "self" }
-								]
-							}
-						]
-					}
+func (s *TestSuite) TestCollectPortsBreakdown() {
+	Convey("Given port breakdown metric types", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+
+		mTypes := []plugin.MetricType{}
+		for _, metricName := range []string{portsCountMetric, portsActiveCountMetric, portsDownCountMetric, portsBuildCountMetric, portsComputeCountMetric, portsDHCPCountMetric, portsRouterInterfaceCountMetric, portsUnboundCountMetric, portsOtherCountMetric} {
+			mTypes = append(mTypes, plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", metricName), Config_: cfg.ConfigDataNode})
+		}
+
+		Convey("When CollectMetrics() is called", func() {
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then ports are counted by status and device owner", func() {
+				So(len(mts), ShouldEqual, 9)
+
+				metrics := map[string]interface{}{}
+				for _, m := range mts {
+					metrics[m.Namespace()[metricNameNSPartNumber].Value] = m.Data()
 				}
-				`, th.Endpoint()+"v3/", th.Endpoint()+"v2.0/")
+				So(metrics[portsCountMetric], ShouldEqual, 3)
+				So(metrics[portsActiveCountMetric], ShouldEqual, 2)
+				So(metrics[portsDownCountMetric], ShouldEqual, 1)
+				So(metrics[portsBuildCountMetric], ShouldEqual, 0)
+				So(metrics[portsComputeCountMetric], ShouldEqual, 1)
+				So(metrics[portsDHCPCountMetric], ShouldEqual, 1)
+				So(metrics[portsRouterInterfaceCountMetric], ShouldEqual, 1)
+				So(metrics[portsUnboundCountMetric], ShouldEqual, 0)
+				So(metrics[portsOtherCountMetric], ShouldEqual, 0)
+			})
+
+			Convey("Then ports are listed only once", func() {
+				So(s.Requests.count("/v2.0/ports"), ShouldEqual, 1)
+			})
+		})
 	})
 }
 
-func registerAuthentication(s *TestSuite) {
-	s.Token = "cefb1b0ba45744488e6ed702db699327"
-	s.NetworkServiceEndpoint = th.Endpoint()
-	th.Mux.HandleFunc("/v2.0/tokens", func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintf(w, `
-				{
-					"access": {
-						"metadata": {
-							"is_admin": 0,
-							"roles": [
-								"dc6ed0c1bfb847c9b087e1d62068766b"
-							]
-						},
-						"serviceCatalog": [
-						{
-								"endpoints": [
-									{
-										"adminURL": "%s",
-										"id": "3ffe125aa59547029ed774c10b932349",
-										"internalURL": "%s",
-										"publicURL": "%s",
-										"region": "RegionOne"
-									}
-								],
-								"endpoints_links": [],
-								"name": "neutron",
-								"type": "network"
-							}
-						],
-						"token": {
-							"expires": "2017-01-19T15:33:11Z",
-							"id": "%s",
-							"issued_at": "2017-01-19T14:33:11.541197Z",
-							"tenant": {
-								"description": "",
-								"enabled": true,
-								"id": "444f244be5e34ce0816e8beccfd332ef",
-								"name": "tenant"
-							}
-						}
-					}
+func (s *TestSuite) TestCollectFloatingIPsBreakdown() {
+	Convey("Given floating IP breakdown metric types", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+
+		mTypes := []plugin.MetricType{}
+		for _, metricName := range []string{floatingipsAssociatedCountMetric, floatingipsUnassociatedCountMetric, floatingipsActiveCountMetric, floatingipsDownCountMetric, floatingipsErrorCountMetric} {
+			mTypes = append(mTypes, plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", metricName), Config_: cfg.ConfigDataNode})
+		}
+		mTypes = append(mTypes, plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, floatingipsUnassociatedCountMetric), Config_: cfg.ConfigDataNode})
+
+		Convey("When CollectMetrics() is called", func() {
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then floating IPs are counted by association and status", func() {
+				So(len(mts), ShouldEqual, 6)
+
+				metrics := map[string]interface{}{}
+				for _, m := range mts {
+					metrics[m.Namespace().String()] = m.Data()
 				}
-			`, s.NetworkServiceEndpoint,
-			s.NetworkServiceEndpoint,
-			s.NetworkServiceEndpoint,
-			s.Token)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, "admin", floatingipsAssociatedCountMetric).String()], ShouldEqual, 1)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, "admin", floatingipsUnassociatedCountMetric).String()], ShouldEqual, 1)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, "admin", floatingipsActiveCountMetric).String()], ShouldEqual, 1)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, "admin", floatingipsDownCountMetric).String()], ShouldEqual, 1)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, "admin", floatingipsErrorCountMetric).String()], ShouldEqual, 0)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, floatingipsUnassociatedCountMetric).String()], ShouldEqual, 2)
+			})
+
+			Convey("Then floating IPs are listed only once", func() {
+				So(s.Requests.count("/v2.0/floatingips"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/networks"), ShouldEqual, 0)
+			})
+		})
 	})
 }
 
-func registerTenants(s *TestSuite) {
-	th.Mux.HandleFunc("/v2.0/tenants", func(w http.ResponseWriter, r *http.Request) {
-		th.TestMethod(s.T(), r, "GET")
-		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+func (s *TestSuite) TestCollectOrphanedMetrics() {
+	Convey("Given orphaned resources metric types", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+		cfg.AddItem(cfgTenantExclude, ctypes.ConfigValueStr{Value: "demo"})
 
-		w.Header().Add("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
+		mTypes := []plugin.MetricType{
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, orphaned+networksCountMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, orphaned+floatingipsCountMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, orphaned+quotas+"port"), Config_: cfg.ConfigDataNode},
+		}
 
-		fmt.Fprintf(w, `
-			{
-				"tenants": [
-					{
-						"description": "demo tenat",
-						"enabled": true,
-						"id": "111111",
-						"name": "demo"
-					},
-					{
-						"description": "admin tenant",
-						"enabled": true,
-						"id": "222222",
-						"name": "admin"
-					}
-				],
-				"tenants_links": []
-			}
-		`)
+		Convey("When CollectMetrics() is called", func() {
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then only resources of tenants which do not exist in Keystone are counted", func() {
+				So(len(mts), ShouldEqual, 2)
+
+				metrics := map[string]interface{}{}
+				for _, m := range mts {
+					metrics[m.Namespace().String()] = m.Data()
+				}
+				// networks of filtered out tenant are not orphaned
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, orphaned+networksCountMetric).String()], ShouldEqual, 0)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, orphaned+floatingipsCountMetric).String()], ShouldEqual, 1)
+			})
+
+			Convey("Then orphaned resources are not listed", func() {
+				So(s.Requests.count("/v2.0/floatingips"), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When CollectMetrics() is called with logging of orphaned resources enabled", func() {
+			cfg.AddItem(cfgLogOrphanedResources, ctypes.ConfigValueBool{Value: true})
+			mTypes = append(mTypes, plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, orphaned+floatingipsDownCountMetric), Config_: cfg.ConfigDataNode})
+
+			var logged bytes.Buffer
+			log.SetOutput(&logged)
+			defer log.SetOutput(os.Stderr)
+
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then resources are not listed again", func() {
+				So(err, ShouldBeNil)
+				So(len(mts), ShouldEqual, 3)
+				So(s.Requests.count("/v2.0/floatingips"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/networks"), ShouldEqual, 1)
+			})
+
+			Convey("Then orphaned resources counted by requested metrics are logged once per family", func() {
+				So(strings.Count(logged.String(), "61cea855-49cb-4846-997d-801b70c71bdd"), ShouldEqual, 1)
+				So(logged.String(), ShouldContainSubstring, "resourceFamily=floatingips")
+				So(logged.String(), ShouldNotContainSubstring, "a75c645a-6dcd-418c-9371-9be7054c395e")
+			})
+		})
 	})
 }
 
-func registerNetworks(s *TestSuite) {
-	th.Mux.HandleFunc("/v2.0/networks", func(w http.ResponseWriter, r *http.Request) {
-		th.TestMethod(s.T(), r, "GET")
-		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+func (s *TestSuite) TestCollectIPAvailabilityMetrics() {
+	Convey("Given network and subnet IP availability metric types", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
 
-		w.Header().Add("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
+		networkNs := func(tenant, network, metricName string) core.Namespace {
+			return core.NewNamespace(vendor, openstack, pluginName, tenant, networkNSPart, network, metricName)
+		}
+		subnetNs := func(tenant, network, subnet, metricName string) core.Namespace {
+			return core.NewNamespace(vendor, openstack, pluginName, tenant, networkNSPart, network, subnetsNSPart, subnet, metricName)
+		}
 
-		fmt.Fprintf(w, `
-			{
-			  "networks": [
-			    {
-			      "admin_state_up": true,
-			      "availability_zone_hints": [],
-			      "availability_zones": [
-				"nova"
-			      ],
-			      "created_at": "2016-09-08T12:01:34",
-			      "description": "",
-			      "id": "28dd974d-0ec0-43cc-86ac-06773acb126f",
-			      "ipv4_address_scope": null,
-			      "ipv6_address_scope": null,
-			      "mtu": 1450,
-			      "name": "private",
-			      "port_security_enabled": true,
-			      "provider:network_type": "vxlan",
-			      "provider:physical_network": null,
-			      "provider:segmentation_id": 19,
-			      "revision": 6,
-			      "router:external": false,
-			      "shared": false,
-			      "status": "ACTIVE",
-			      "subnets": [
-				"ef512c07-9203-4121-9df6-e692a4bc84c5",
-				"64c8fbe0-cb8a-41d7-9e65-56f33f9674cb"
-			      ],
-			      "tags": [],
-			      "tenant_id": "111111",
-			      "updated_at": "2016-09-08T12:01:38"
-			    },
-			    {
-			      "admin_state_up": true,
-			      "availability_zone_hints": [],
-			      "availability_zones": [
-				"nova"
-			      ],
-			      "created_at": "2016-09-08T12:01:51",
-			      "description": "",
-			      "id": "f3722668-e9e7-41dd-8086-5e1b9f5d8209",
-			      "ipv4_address_scope": null,
-			      "ipv6_address_scope": null,
-			      "is_default": true,
-			      "mtu": 1500,
-			      "name": "public",
-			      "port_security_enabled": true,
-			      "provider:network_type": "flat",
-			      "provider:physical_network": "public",
-			      "provider:segmentation_id": null,
-			      "revision": 6,
-			      "router:external": true,
-			      "shared": false,
-			      "status": "ACTIVE",
-			      "subnets": [
-				"4582d819-7ded-4ec5-aa92-27f73781f625",
-				"94daf3aa-6faf-43c0-a21c-9656110b3d11"
-			      ],
-			      "tags": [],
-			      "tenant_id": "222222",
-			      "updated_at": "2016-09-08T12:02:05"
-			    },
-			    {
-			      "admin_state_up": true,
-			      "availability_zone_hints": [],
-			      "availability_zones": [
-				"nova"
-			      ],
-			      "created_at": "2016-09-08T12:01:51",
-			      "description": "",
-			      "id": "f3722668-e9e7-41dd-8086-5e1b9f5d8209",
-			      "ipv4_address_scope": null,
-			      "ipv6_address_scope": null,
-			      "is_default": true,
-			      "mtu": 1500,
-			      "name": "public",
-			      "port_security_enabled": true,
-			      "provider:network_type": "flat",
-			      "provider:physical_network": "public",
-			      "provider:segmentation_id": null,
-			      "revision": 6,
-			      "router:external": true,
-			      "shared": false,
-			      "status": "ACTIVE",
-			      "subnets": [
-				"4582d819-7ded-4ec5-aa92-27f73781f625",
-				"94daf3aa-6faf-43c0-a21c-9656110b3d11"
-			      ],
-			      "tags": [],
-			      "tenant_id": "222222",
-			      "updated_at": "2016-09-08T12:02:05"
-			    }
-			  ]
+		mTypes := []plugin.MetricType{
+			plugin.MetricType{Namespace_: networkNs("*", "*", ipTotalMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: networkNs("admin", "*", ipUsedMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: subnetNs("demo", "28dd974d-0ec0-43cc-86ac-06773acb126f", "*", ipUtilizationMetric), Config_: cfg.ConfigDataNode},
+		}
+
+		collect := func() map[string]interface{} {
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+			So(err, ShouldBeNil)
+
+			metrics := map[string]interface{}{}
+			for _, m := range mts {
+				metrics[m.Namespace().String()] = m.Data()
 			}
-		`)
+			return metrics
+		}
+
+		Convey("When CollectMetrics() is called and network IP availability extension is available", func() {
+			metrics := collect()
+
+			Convey("Then availability reported by Neutron is returned for networks of each tenant", func() {
+				So(len(metrics), ShouldEqual, 4)
+				So(metrics[networkNs("admin", "f3722668-e9e7-41dd-8086-5e1b9f5d8209", ipTotalMetric).String()], ShouldEqual, 253)
+				So(metrics[networkNs("demo", "28dd974d-0ec0-43cc-86ac-06773acb126f", ipTotalMetric).String()], ShouldEqual, 253)
+				So(metrics[networkNs("admin", "f3722668-e9e7-41dd-8086-5e1b9f5d8209", ipUsedMetric).String()], ShouldEqual, 12)
+				So(metrics[subnetNs("demo", "28dd974d-0ec0-43cc-86ac-06773acb126f", "64c8fbe0-cb8a-41d7-9e65-56f33f9674cb", ipUtilizationMetric).String()], ShouldAlmostEqual, 300.0/253)
+			})
+
+			Convey("Then no resources are listed to calculate availability", func() {
+				So(s.Requests.count("/v2.0/network-ip-availabilities"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/subnets"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/ports"), ShouldEqual, 0)
+			})
+		})
+
+		for _, status := range []int{http.StatusNotFound, http.StatusForbidden} {
+			status := status
+			Convey(fmt.Sprintf("When CollectMetrics() is called and network IP availability extension responds with %d", status), func() {
+				s.IPAvailabilityStatus = status
+				defer func() { s.IPAvailabilityStatus = 0 }()
+				metrics := collect()
+
+				Convey("Then availability is calculated from allocation pools of subnets and fixed IPs of ports", func() {
+					So(len(metrics), ShouldEqual, 4)
+					So(metrics[networkNs("demo", "28dd974d-0ec0-43cc-86ac-06773acb126f", ipTotalMetric).String()], ShouldEqual, 253)
+					So(metrics[networkNs("admin", "f3722668-e9e7-41dd-8086-5e1b9f5d8209", ipUsedMetric).String()], ShouldEqual, 0)
+					So(metrics[subnetNs("demo", "28dd974d-0ec0-43cc-86ac-06773acb126f", "64c8fbe0-cb8a-41d7-9e65-56f33f9674cb", ipUtilizationMetric).String()], ShouldAlmostEqual, 300.0/253)
+				})
+
+				Convey("Then number of addresses of large IPv6 subnet is capped", func() {
+					So(metrics[networkNs("admin", "f3722668-e9e7-41dd-8086-5e1b9f5d8209", ipTotalMetric).String()], ShouldEqual, int64(math.MaxInt64))
+				})
+
+				Convey("Then networks, subnets and ports are listed once", func() {
+					So(s.Requests.count("/v2.0/networks"), ShouldEqual, 1)
+					So(s.Requests.count("/v2.0/subnets"), ShouldEqual, 1)
+					So(s.Requests.count("/v2.0/ports"), ShouldEqual, 1)
+				})
+			})
+		}
+
+		Convey("When CollectMetrics() is called together with counts of networks, subnets and ports and network IP availability extension is not available", func() {
+			s.IPAvailabilityStatus = http.StatusNotFound
+			defer func() { s.IPAvailabilityStatus = 0 }()
+			mTypes = append(mTypes,
+				plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", networksCountMetric), Config_: cfg.ConfigDataNode},
+				plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", subnetsCountMetric), Config_: cfg.ConfigDataNode},
+				plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", portsCountMetric), Config_: cfg.ConfigDataNode},
+			)
+			metrics := collect()
+
+			Convey("Then all metrics are returned and networks, subnets and ports are listed once", func() {
+				So(len(metrics), ShouldEqual, 7)
+				So(s.Requests.count("/v2.0/networks"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/subnets"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/ports"), ShouldEqual, 1)
+			})
+		})
 	})
 }
 
-func registerSubnets(s *TestSuite) {
-	th.Mux.HandleFunc("/v2.0/subnets", func(w http.ResponseWriter, r *http.Request) {
-		th.TestMethod(s.T(), r, "GET")
-		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+func (s *TestSuite) TestCollectNetworkAndRouterMetrics() {
+	Convey("Given metric types of single networks and routers", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
 
-		w.Header().Add("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
+		networkNs := func(tenant, network, metricName string) core.Namespace {
+			return core.NewNamespace(vendor, openstack, pluginName, tenant, networkNSPart, network, metricName)
+		}
+		routerNs := func(tenant, router string) core.Namespace {
+			return core.NewNamespace(vendor, openstack, pluginName, tenant, routerNSPart, router, interfacesCountMetric)
+		}
 
-		fmt.Fprintf(w, `
-			{
-			  "subnets": [
-			    {
-			      "allocation_pools": [
-				{
-				  "end": "2001:db8::1",
-				  "start": "2001:db8::1"
-				},
-				{
-				  "end": "2001:db8::ffff:ffff:ffff:ffff",
-				  "start": "2001:db8::3"
-				}
-			      ],
-			      "cidr": "2001:db8::/64",
-			      "created_at": "2016-09-08T12:02:05",
-			      "description": "",
-			      "dns_nameservers": [],
+		mTypes := []plugin.MetricType{
+			plugin.MetricType{Namespace_: networkNs("*", "*", portsCountMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: networkNs("admin", "f3722668-e9e7-41dd-8086-5e1b9f5d8209", subnetsCountMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: routerNs("admin", "*"), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", routerNSPart, "*", portsCountMetric), Config_: cfg.ConfigDataNode},
+		}
+
+		Convey("When CollectMetrics() is called", func() {
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then metrics are returned for each network and router of tenants", func() {
+				So(len(mts), ShouldEqual, 4)
+
+				metrics := map[string]plugin.MetricType{}
+				for _, m := range mts {
+					metrics[m.Namespace().String()] = m
+				}
+
+				private := metrics[networkNs("demo", "28dd974d-0ec0-43cc-86ac-06773acb126f", portsCountMetric).String()]
+				So(private.Data(), ShouldEqual, 3)
+				So(private.Tags()["network_name"], ShouldEqual, "private")
+				So(private.Tags()["shared"], ShouldEqual, "false")
+				So(private.Tags()["external"], ShouldEqual, "false")
+				So(private.Tags()[tenantNameElement], ShouldEqual, "demo")
+
+				public := metrics[networkNs("admin", "f3722668-e9e7-41dd-8086-5e1b9f5d8209", portsCountMetric).String()]
+				So(public.Data(), ShouldEqual, 0)
+				So(public.Tags()["external"], ShouldEqual, "true")
+
+				So(metrics[networkNs("admin", "f3722668-e9e7-41dd-8086-5e1b9f5d8209", subnetsCountMetric).String()].Data(), ShouldEqual, 2)
+
+				router := metrics[routerNs("admin", "a75c645a-6dcc-418c-9371-9be7054c395e").String()]
+				So(router.Data(), ShouldEqual, 1)
+				So(router.Tags()["router_name"], ShouldEqual, "router1")
+				So(router.Tags()["external"], ShouldEqual, "true")
+			})
+
+			Convey("Then resources are listed once and no quotas are retrieved", func() {
+				So(s.Requests.count("/v2.0/networks"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/ports"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/routers"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/quotas/222222/details"), ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestCollectAgentMetrics() {
+	Convey("Given metric types of Neutron agents", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+
+		agentNs := func(agentType, host, metricName string) core.Namespace {
+			return core.NewNamespace(vendor, openstack, pluginName, agentsNSPart, agentType, host, metricName)
+		}
+		agentTypeNs := func(agentType, metricName string) core.Namespace {
+			return core.NewNamespace(vendor, openstack, pluginName, agentsNSPart, agentType, metricName)
+		}
+
+		mTypes := []plugin.MetricType{
+			plugin.MetricType{Namespace_: agentNs("*", "*", agentAliveMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: agentNs("*", "compute-1", agentAdminStateUpMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: agentNs("dhcp_agent", "*", agentHeartbeatAgeMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: agentTypeNs("*", agentsDownCountMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: agentTypeNs("l3_agent", agentsAliveCountMetric), Config_: cfg.ConfigDataNode},
+		}
+
+		Convey("When CollectMetrics() is called", func() {
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then metrics are returned for each matching agent and agent type", func() {
+				So(len(mts), ShouldEqual, 9)
+
+				metrics := map[string]plugin.MetricType{}
+				for _, m := range mts {
+					metrics[m.Namespace().String()] = m
+				}
+
+				l3 := metrics[agentNs("l3_agent", "network-1", agentAliveMetric).String()]
+				So(l3.Data(), ShouldEqual, 1)
+				So(l3.Tags()["agent_type"], ShouldEqual, "L3 agent")
+				So(l3.Tags()["agent_id"], ShouldEqual, "04c62b91-b799-48b7-9cd5-2982db6df9c6")
+				So(l3.Tags()["binary"], ShouldEqual, "neutron-l3-agent")
+				So(l3.Tags()["availability_zone"], ShouldEqual, "nova")
+				So(metrics[agentNs("dhcp_agent", "network-1", agentAliveMetric).String()].Data(), ShouldEqual, 0)
+				So(metrics[agentNs("open_vswitch_agent", "compute-1", agentAliveMetric).String()].Data(), ShouldEqual, 1)
+
+				So(metrics[agentNs("open_vswitch_agent", "compute-1", agentAdminStateUpMetric).String()].Data(), ShouldEqual, 0)
+
+				age := metrics[agentNs("dhcp_agent", "network-1", agentHeartbeatAgeMetric).String()]
+				So(age.Data(), ShouldBeGreaterThanOrEqualTo, 3600)
+				So(age.Data(), ShouldBeLessThan, 3700)
+
+				So(metrics[agentTypeNs("dhcp_agent", agentsDownCountMetric).String()].Data(), ShouldEqual, 1)
+				So(metrics[agentTypeNs("l3_agent", agentsDownCountMetric).String()].Data(), ShouldEqual, 0)
+				So(metrics[agentTypeNs("open_vswitch_agent", agentsDownCountMetric).String()].Data(), ShouldEqual, 0)
+				So(metrics[agentTypeNs("l3_agent", agentsAliveCountMetric).String()].Data(), ShouldEqual, 1)
+			})
+
+			Convey("Then agents are listed once and no tenant resources are retrieved", func() {
+				So(s.Requests.count("/v2.0/agents"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/networks"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/quotas/222222/details"), ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestCollectLoadBalancerMetrics() {
+	Convey("Given metric types of load balancing resources", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+
+		metricNames := []string{
+			"loadbalancers_count",
+			"loadbalancers_provisioning_pending_count",
+			"listeners_count",
+			"pools_count",
+			"members_count",
+			"members_operating_error_count",
+			"members_operating_online_count",
+			"healthmonitors_operating_online_count",
+			utilization + "loadbalancer",
+			headroom + "member",
+		}
+		mTypes := []plugin.MetricType{}
+		for _, metricName := range metricNames {
+			mTypes = append(mTypes, plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", metricName), Config_: cfg.ConfigDataNode})
+		}
+		mTypes = append(mTypes, plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "demo", "loadbalancers_provisioning_pending_count"), Config_: cfg.ConfigDataNode})
+		mTypes = append(mTypes, plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, "members_count"), Config_: cfg.ConfigDataNode})
+
+		Convey("When CollectMetrics() is called", func() {
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then load balancing resources are counted per tenant and status", func() {
+				So(len(mts), ShouldEqual, 12)
+
+				metrics := map[string]interface{}{}
+				for _, m := range mts {
+					metrics[m.Namespace().String()] = m.Data()
+				}
+				adminMetric := func(metricName string) string {
+					return core.NewNamespace(vendor, openstack, pluginName, "admin", metricName).String()
+				}
+
+				So(metrics[adminMetric("loadbalancers_count")], ShouldEqual, 1)
+				So(metrics[adminMetric("loadbalancers_provisioning_pending_count")], ShouldEqual, 0)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, "demo", "loadbalancers_provisioning_pending_count").String()], ShouldEqual, 1)
+				So(metrics[adminMetric("listeners_count")], ShouldEqual, 1)
+				So(metrics[adminMetric("pools_count")], ShouldEqual, 2)
+				So(metrics[adminMetric("members_count")], ShouldEqual, 2)
+				So(metrics[adminMetric("members_operating_error_count")], ShouldEqual, 1)
+				So(metrics[adminMetric("members_operating_online_count")], ShouldEqual, 1)
+				So(metrics[adminMetric("healthmonitors_operating_online_count")], ShouldEqual, 1)
+				So(metrics[adminMetric(utilization+"loadbalancer")], ShouldEqual, 0.1)
+				So(metrics[adminMetric(headroom+"member")], ShouldEqual, unlimitedQuota)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, "members_count").String()], ShouldEqual, 2)
+			})
+
+			Convey("Then members are listed only for pools which have members", func() {
+				So(s.Requests.count("/v2.0/lbaas/loadbalancers"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/lbaas/pools"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/lbaas/pools/4c0a0a5f-cf8f-44b7-b912-957daa8ce5e5/members"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/lbaas/pools/d7fe2a5a-9c52-4de9-a3dc-4e4d0a3c9a52/members"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/networks"), ShouldEqual, 0)
+			})
+
+			Convey("Then Neutron LBaaS v2 extension is discovered and quotas are not requested from Octavia", func() {
+				So(s.Requests.count("/v2.0/extensions/lbaasv2"), ShouldEqual, 1)
+				So(s.Requests.count("/octavia/v2/lbaas/quotas/222222"), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When CollectMetrics() is called and Neutron LBaaS v2 extension is not loaded", func() {
+			s.UnavailableExtensions = map[string]bool{"lbaasv2": true}
+			defer func() { s.UnavailableExtensions = nil }()
+
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then metrics of load balancing resources are skipped", func() {
+				So(mts, ShouldBeEmpty)
+				So(s.Requests.count("/v2.0/lbaas/loadbalancers"), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When CollectMetrics() is called and Octavia is present in service catalog", func() {
+			s.OctaviaEndpoint = th.Endpoint() + "octavia/"
+			defer func() { s.OctaviaEndpoint = "" }()
+
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+				So(len(mts), ShouldEqual, 12)
+			})
+
+			Convey("Then quotas of load balancing resources are taken from Octavia", func() {
+				metrics := map[string]interface{}{}
+				for _, m := range mts {
+					metrics[m.Namespace().String()] = m.Data()
+				}
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, "admin", utilization+"loadbalancer").String()], ShouldEqual, 0.2)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, "admin", headroom+"member").String()], ShouldEqual, unlimitedQuota)
+				So(s.Requests.count("/octavia/v2/lbaas/quotas/222222"), ShouldEqual, 1)
+				So(s.Requests.count("/octavia/v2/lbaas/quotas/111111"), ShouldEqual, 1)
+			})
+
+			Convey("Then load balancing resources are retrieved from Octavia without extension discovery", func() {
+				So(s.Requests.count("/v2.0/extensions/lbaasv2"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/lbaas/loadbalancers"), ShouldEqual, 0)
+				So(s.Requests.count("/octavia/v2/lbaas/loadbalancers"), ShouldEqual, 1)
+				So(s.Requests.count("/octavia/v2/lbaas/pools/4c0a0a5f-cf8f-44b7-b912-957daa8ce5e5/members"), ShouldEqual, 1)
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestCollectVPNMetrics() {
+	Convey("Given metric types of VPN resources", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+
+		metricNames := []string{
+			ikePoliciesCountMetric,
+			ipsecPoliciesCountMetric,
+			vpnServicesCountMetric,
+			ipsecSiteConnectionsCountMetric,
+			ipsecSiteConnectionsActiveCountMetric,
+			ipsecSiteConnectionsDownCountMetric,
+			ipsecSiteConnectionsErrorCountMetric,
+			utilization + "ipsec_site_connection",
+			headroom + "ipsec_site_connection",
+		}
+		mTypes := []plugin.MetricType{}
+		for _, metricName := range metricNames {
+			mTypes = append(mTypes, plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", metricName), Config_: cfg.ConfigDataNode})
+		}
+		mTypes = append(mTypes, plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "demo", ipsecSiteConnectionsErrorCountMetric), Config_: cfg.ConfigDataNode})
+
+		Convey("When CollectMetrics() is called", func() {
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then VPN resources are counted per tenant and site connections per status", func() {
+				So(len(mts), ShouldEqual, 10)
+
+				metrics := map[string]interface{}{}
+				for _, m := range mts {
+					metrics[m.Namespace().String()] = m.Data()
+				}
+				adminMetric := func(metricName string) string {
+					return core.NewNamespace(vendor, openstack, pluginName, "admin", metricName).String()
+				}
+
+				So(metrics[adminMetric(ikePoliciesCountMetric)], ShouldEqual, 1)
+				So(metrics[adminMetric(ipsecPoliciesCountMetric)], ShouldEqual, 2)
+				So(metrics[adminMetric(vpnServicesCountMetric)], ShouldEqual, 1)
+				So(metrics[adminMetric(ipsecSiteConnectionsCountMetric)], ShouldEqual, 3)
+				So(metrics[adminMetric(ipsecSiteConnectionsActiveCountMetric)], ShouldEqual, 1)
+				So(metrics[adminMetric(ipsecSiteConnectionsDownCountMetric)], ShouldEqual, 1)
+				So(metrics[adminMetric(ipsecSiteConnectionsErrorCountMetric)], ShouldEqual, 0)
+				So(metrics[adminMetric(utilization+"ipsec_site_connection")], ShouldEqual, 0.3)
+				So(metrics[adminMetric(headroom+"ipsec_site_connection")], ShouldEqual, 7)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, "demo", ipsecSiteConnectionsErrorCountMetric).String()], ShouldEqual, 1)
+			})
+
+			Convey("Then extension is discovered once and each VPN collection is listed once", func() {
+				So(s.Requests.count("/v2.0/extensions/vpnaas"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/vpn/ikepolicies"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/vpn/ipsecpolicies"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/vpn/vpnservices"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/vpn/ipsec-site-connections"), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When CollectMetrics() is called and VPNaaS extension is not loaded", func() {
+			s.UnavailableExtensions = map[string]bool{"vpnaas": true}
+			defer func() { s.UnavailableExtensions = nil }()
+
+			errorMetrics := []plugin.MetricType{}
+			for _, family := range []string{ikePoliciesFamily, ipsecPoliciesFamily, vpnServicesFamily, ipsecSiteConnsFamily} {
+				errorMetrics = append(errorMetrics, plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, errorsNSPart, family), Config_: cfg.ConfigDataNode})
+			}
+
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(append(mTypes, errorMetrics...))
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then VPN metrics are skipped and resource families are not regarded as failed", func() {
+				So(len(mts), ShouldEqual, 4)
+				for _, m := range mts {
+					So(m.Namespace()[tenantNameNSPartNumber].Value, ShouldEqual, errorsNSPart)
+					So(m.Data(), ShouldEqual, 0)
+				}
+			})
+
+			Convey("Then VPN collections are not listed", func() {
+				So(s.Requests.count("/v2.0/extensions/vpnaas"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/vpn/ikepolicies"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/vpn/ipsecpolicies"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/vpn/vpnservices"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/vpn/ipsec-site-connections"), ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestCollectRBACPolicyAndSubnetPoolMetrics() {
+	Convey("Given metric types of RBAC policies and subnet pools", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+
+		metricNames := []string{
+			rbacPoliciesCountMetric,
+			rbacPoliciesNetworkCountMetric,
+			rbacPoliciesQoSPolicyCountMetric,
+			rbacPoliciesSecurityGroupCountMetric,
+			rbacPoliciesSharedCountMetric,
+			rbacPoliciesExternalCountMetric,
+			subnetPoolsCountMetric,
+		}
+		mTypes := []plugin.MetricType{}
+		for _, metricName := range metricNames {
+			mTypes = append(mTypes, plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", metricName), Config_: cfg.ConfigDataNode})
+		}
+		for _, metricName := range subnetPoolMetrics {
+			mTypes = append(mTypes, plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "*", subnetPoolsNSPart, "*", metricName), Config_: cfg.ConfigDataNode})
+		}
+
+		Convey("When CollectMetrics() is called", func() {
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then RBAC policies are counted per object type and action", func() {
+				metrics := map[string]interface{}{}
+				for _, m := range mts {
+					metrics[m.Namespace().String()] = m.Data()
+				}
+				adminMetric := func(metricName string) string {
+					return core.NewNamespace(vendor, openstack, pluginName, "admin", metricName).String()
+				}
+
+				So(metrics[adminMetric(rbacPoliciesCountMetric)], ShouldEqual, 3)
+				So(metrics[adminMetric(rbacPoliciesNetworkCountMetric)], ShouldEqual, 2)
+				So(metrics[adminMetric(rbacPoliciesQoSPolicyCountMetric)], ShouldEqual, 1)
+				So(metrics[adminMetric(rbacPoliciesSecurityGroupCountMetric)], ShouldEqual, 0)
+				So(metrics[adminMetric(rbacPoliciesSharedCountMetric)], ShouldEqual, 2)
+				So(metrics[adminMetric(rbacPoliciesExternalCountMetric)], ShouldEqual, 1)
+				So(metrics[adminMetric(subnetPoolsCountMetric)], ShouldEqual, 1)
+			})
+
+			Convey("Then address space of each subnet pool is reported", func() {
+				So(len(mts), ShouldEqual, 13)
+
+				metrics := map[string]plugin.MetricType{}
+				for _, m := range mts {
+					metrics[m.Namespace().String()] = m
+				}
+				poolMetric := func(tenant, id, metricName string) plugin.MetricType {
+					return metrics[core.NewNamespace(vendor, openstack, pluginName, tenant, subnetPoolsNSPart, id, metricName).String()]
+				}
+
+				m := poolMetric("admin", "f49a1319-423a-4ee6-ba54-1d95a4f6cc68", prefixCapacityMetric)
+				So(m.Data(), ShouldEqual, 65536)
+				So(m.Tags()["subnetpool_name"], ShouldEqual, "pool-v4")
+				So(m.Tags()["ip_version"], ShouldEqual, "4")
+				So(m.Tags()["shared"], ShouldEqual, "false")
+				So(m.Tags()["tenant_id"], ShouldEqual, "222222")
+				So(poolMetric("admin", "f49a1319-423a-4ee6-ba54-1d95a4f6cc68", prefixFreeMetric).Data(), ShouldEqual, 65280)
+				So(poolMetric("admin", "f49a1319-423a-4ee6-ba54-1d95a4f6cc68", prefixUtilizationMetric).Data(), ShouldAlmostEqual, 0.390625)
+
+				So(poolMetric("demo", "2fe7a1ae-6fa6-4b2b-8d2f-e8f5d0b1c9a3", prefixCapacityMetric).Data(), ShouldEqual, 512)
+				So(poolMetric("demo", "2fe7a1ae-6fa6-4b2b-8d2f-e8f5d0b1c9a3", prefixFreeMetric).Data(), ShouldEqual, 512)
+				So(poolMetric("demo", "2fe7a1ae-6fa6-4b2b-8d2f-e8f5d0b1c9a3", prefixUtilizationMetric).Data(), ShouldEqual, 0)
+			})
+
+			Convey("Then RBAC policies, subnet pools and subnets are listed once", func() {
+				So(s.Requests.count("/v2.0/rbac-policies"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/subnetpools"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/subnets"), ShouldEqual, 1)
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestCollectQoSMetrics() {
+	Convey("Given metric types of QoS policies and resources with QoS policy attached", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+
+		metricNames := []string{
+			qosPoliciesCountMetric,
+			qosRulesCountMetric,
+			qosRulesBandwidthLimitCountMetric,
+			qosRulesDSCPMarkingCountMetric,
+			qosRulesMinimumBandwidthCountMetric,
+			portsQoSPolicyCountMetric,
+			networksQoSPolicyCountMetric,
+		}
+		mTypes := []plugin.MetricType{}
+		for _, metricName := range metricNames {
+			mTypes = append(mTypes, plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", metricName), Config_: cfg.ConfigDataNode})
+		}
+		mTypes = append(mTypes, plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "demo", qosRulesMinimumBandwidthCountMetric), Config_: cfg.ConfigDataNode})
+
+		Convey("When CollectMetrics() is called", func() {
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then QoS policies and rules are counted per tenant and rule type", func() {
+				So(len(mts), ShouldEqual, 8)
+
+				metrics := map[string]interface{}{}
+				for _, m := range mts {
+					metrics[m.Namespace().String()] = m.Data()
+				}
+				adminMetric := func(metricName string) string {
+					return core.NewNamespace(vendor, openstack, pluginName, "admin", metricName).String()
+				}
+
+				So(metrics[adminMetric(qosPoliciesCountMetric)], ShouldEqual, 2)
+				So(metrics[adminMetric(qosRulesCountMetric)], ShouldEqual, 3)
+				So(metrics[adminMetric(qosRulesBandwidthLimitCountMetric)], ShouldEqual, 2)
+				So(metrics[adminMetric(qosRulesDSCPMarkingCountMetric)], ShouldEqual, 1)
+				So(metrics[adminMetric(qosRulesMinimumBandwidthCountMetric)], ShouldEqual, 0)
+				So(metrics[adminMetric(portsQoSPolicyCountMetric)], ShouldEqual, 1)
+				So(metrics[adminMetric(networksQoSPolicyCountMetric)], ShouldEqual, 1)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, "demo", qosRulesMinimumBandwidthCountMetric).String()], ShouldEqual, 1)
+			})
+
+			Convey("Then listings of ports and networks are reused", func() {
+				So(s.Requests.count("/v2.0/qos/policies"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/ports"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/networks"), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When CollectMetrics() is called and QoS extension is not loaded", func() {
+			s.UnavailableExtensions = map[string]bool{"qos": true}
+			defer func() { s.UnavailableExtensions = nil }()
+
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then only metrics of ports and networks are reported", func() {
+				So(len(mts), ShouldEqual, 2)
+				for _, m := range mts {
+					So(m.Namespace()[metricNameNSPartNumber].Value, ShouldBeIn, []string{portsQoSPolicyCountMetric, networksQoSPolicyCountMetric})
+				}
+			})
+
+			Convey("Then QoS policies are not listed", func() {
+				So(s.Requests.count("/v2.0/extensions/qos"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/qos/policies"), ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestCollectFWaaSMetrics() {
+	Convey("Given metric types of FWaaS resources", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+
+		metricNames := []string{
+			firewallGroupsCountMetric,
+			firewallGroupsActiveCountMetric,
+			firewallGroupsInactiveCountMetric,
+			firewallGroupsDownCountMetric,
+			firewallGroupsErrorCountMetric,
+			firewallGroupsPendingCountMetric,
+			firewallGroupPortsCountMetric,
+			firewallPoliciesCountMetric,
+			firewallRulesCountMetric,
+			headroom + "firewall_group",
+		}
+		mTypes := []plugin.MetricType{}
+		for _, metricName := range metricNames {
+			mTypes = append(mTypes, plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", metricName), Config_: cfg.ConfigDataNode})
+		}
+		mTypes = append(mTypes,
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "demo", firewallGroupsErrorCountMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, firewallRulesCountMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, errorsNSPart, fwaasFamily), Config_: cfg.ConfigDataNode},
+		)
+
+		Convey("When CollectMetrics() is called and FWaaS extension is loaded", func() {
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then firewall groups are counted per tenant and status together with their ports", func() {
+				So(len(mts), ShouldEqual, 13)
+
+				metrics := map[string]interface{}{}
+				for _, m := range mts {
+					metrics[m.Namespace().String()] = m.Data()
+				}
+				adminMetric := func(metricName string) string {
+					return core.NewNamespace(vendor, openstack, pluginName, "admin", metricName).String()
+				}
+
+				So(metrics[adminMetric(firewallGroupsCountMetric)], ShouldEqual, 3)
+				So(metrics[adminMetric(firewallGroupsActiveCountMetric)], ShouldEqual, 1)
+				So(metrics[adminMetric(firewallGroupsInactiveCountMetric)], ShouldEqual, 1)
+				So(metrics[adminMetric(firewallGroupsDownCountMetric)], ShouldEqual, 0)
+				So(metrics[adminMetric(firewallGroupsErrorCountMetric)], ShouldEqual, 0)
+				So(metrics[adminMetric(firewallGroupsPendingCountMetric)], ShouldEqual, 1)
+				So(metrics[adminMetric(firewallGroupPortsCountMetric)], ShouldEqual, 3)
+				So(metrics[adminMetric(firewallPoliciesCountMetric)], ShouldEqual, 2)
+				So(metrics[adminMetric(firewallRulesCountMetric)], ShouldEqual, 2)
+				So(metrics[adminMetric(headroom+"firewall_group")], ShouldEqual, 7)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, "demo", firewallGroupsErrorCountMetric).String()], ShouldEqual, 1)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, aggregateNSPart, firewallRulesCountMetric).String()], ShouldEqual, 3)
+				So(metrics[core.NewNamespace(vendor, openstack, pluginName, errorsNSPart, fwaasFamily).String()], ShouldEqual, 0)
+			})
+
+			Convey("Then extension is discovered and each FWaaS collection is listed once", func() {
+				So(s.Requests.count("/v2.0/extensions/fwaas_v2"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/fwaas/firewall_groups"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/fwaas/firewall_policies"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/fwaas/firewall_rules"), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When CollectMetrics() is called and FWaaS extension is not loaded", func() {
+			s.UnavailableExtensions = map[string]bool{"fwaas_v2": true}
+			defer func() { s.UnavailableExtensions = nil }()
+
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then FWaaS metrics are skipped and resource family is not regarded as failed", func() {
+				So(len(mts), ShouldEqual, 1)
+				So(mts[0].Namespace().String(), ShouldEqual, core.NewNamespace(vendor, openstack, pluginName, errorsNSPart, fwaasFamily).String())
+				So(mts[0].Data(), ShouldEqual, 0)
+			})
+
+			Convey("Then FWaaS collections are not listed", func() {
+				So(s.Requests.count("/v2.0/extensions/fwaas_v2"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/fwaas/firewall_groups"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/fwaas/firewall_policies"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/fwaas/firewall_rules"), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When CollectMetrics() is called and discovery of FWaaS extension fails", func() {
+			s.FailingExtensions = map[string]bool{"fwaas_v2": true}
+			defer func() { s.FailingExtensions = nil }()
+
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then FWaaS metrics are skipped and resource family is regarded as failed", func() {
+				So(len(mts), ShouldEqual, 1)
+				So(mts[0].Namespace().String(), ShouldEqual, core.NewNamespace(vendor, openstack, pluginName, errorsNSPart, fwaasFamily).String())
+				So(mts[0].Data(), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/fwaas/firewall_groups"), ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestCollectMetricsPartialFailure() {
+	Convey("Given set of metric types and Neutron endpoint with unavailable floating IPs", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+		s.FloatingIPsUnavailable = true
+		defer func() { s.FloatingIPsUnavailable = false }()
+
+		mTypes := []plugin.MetricType{
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", networksCountMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", floatingipsCountMetric), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", quotas+"floatingip"), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", utilization+"floatingip"), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", headroom+"network"), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, errorsNSPart, floatingipsFamily), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, errorsNSPart, networksFamily), Config_: cfg.ConfigDataNode},
+		}
+
+		Convey("When CollectMetrics() is called", func() {
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then no error should be reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then metrics of failed resource family should be skipped", func() {
+				So(len(mts), ShouldEqual, 5)
+
+				metrics := map[string]plugin.MetricType{}
+				for _, m := range mts {
+					metrics[m.Namespace().String()] = m
+				}
+
+				So(metrics, ShouldNotContainKey, core.NewNamespace(vendor, openstack, pluginName, "admin", floatingipsCountMetric).String())
+				So(metrics, ShouldNotContainKey, core.NewNamespace(vendor, openstack, pluginName, "admin", utilization+"floatingip").String())
+
+				m := metrics[core.NewNamespace(vendor, openstack, pluginName, "admin", networksCountMetric).String()]
+				So(m.Data(), ShouldEqual, 2)
+				m = metrics[core.NewNamespace(vendor, openstack, pluginName, "admin", quotas+"floatingip").String()]
+				So(m.Data(), ShouldEqual, 50)
+				m = metrics[core.NewNamespace(vendor, openstack, pluginName, "admin", headroom+"network").String()]
+				So(m.Data(), ShouldEqual, 11)
+			})
+
+			Convey("Then failed resource family should be reported in error metric", func() {
+				metrics := map[string]plugin.MetricType{}
+				for _, m := range mts {
+					metrics[m.Namespace().String()] = m
+				}
+
+				m := metrics[core.NewNamespace(vendor, openstack, pluginName, errorsNSPart, floatingipsFamily).String()]
+				So(m.Data(), ShouldEqual, 1)
+				So(m.Tags(), ShouldContainKey, "error")
+				m = metrics[core.NewNamespace(vendor, openstack, pluginName, errorsNSPart, networksFamily).String()]
+				So(m.Data(), ShouldEqual, 0)
+				So(m.Tags(), ShouldNotContainKey, "error")
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestCollectMetricsOnDemand() {
+	Convey("Given metric types which require only quotas of one tenant", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+
+		mTypes := []plugin.MetricType{
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", quotas+"port"), Config_: cfg.ConfigDataNode},
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", quotas+"network"), Config_: cfg.ConfigDataNode},
+		}
+
+		Convey("When CollectMetrics() is called", func() {
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then requested metrics are returned", func() {
+				So(err, ShouldBeNil)
+				So(len(mts), ShouldEqual, 2)
+			})
+
+			Convey("Then only quotas of requested tenant are retrieved", func() {
+				So(s.Requests.count("/v2.0/quotas/222222/details"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/quotas/111111/details"), ShouldEqual, 0)
+			})
+
+			Convey("Then resources which are not needed are not retrieved", func() {
+				So(s.Requests.count("/v2.0/networks"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/subnets"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/routers"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/ports"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/floatingips"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/security-groups"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/security-group-rules"), ShouldEqual, 0)
+			})
+		})
+	})
+
+	Convey("Given metric types which require usage of quota", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+
+		mTypes := []plugin.MetricType{
+			plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "demo", headroom+"port"), Config_: cfg.ConfigDataNode},
+		}
+
+		Convey("When CollectMetrics() is called", func() {
+			s.Requests.reset()
+			collector := New()
+			mts, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then requested metric is returned", func() {
+				So(err, ShouldBeNil)
+				So(len(mts), ShouldEqual, 1)
+			})
+
+			Convey("Then quotas and used resources are retrieved", func() {
+				So(s.Requests.count("/v2.0/quotas/111111/details"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/quotas/222222/details"), ShouldEqual, 0)
+				So(s.Requests.count("/v2.0/ports"), ShouldEqual, 1)
+				So(s.Requests.count("/v2.0/networks"), ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetListOpts() {
+	Convey("Given config without listing options", s.T(), func() {
+		cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+
+		Convey("Then default page size is used and all fields are retrieved", func() {
+			opts := getListOpts(cfg)
+			So(opts.Limit, ShouldEqual, defaultPageSize)
+			So(opts.Fields, ShouldBeEmpty)
+		})
+
+		Convey("When page size and tenant ID fields are configured", func() {
+			cfg.AddItem(cfgPageSize, ctypes.ConfigValueInt{Value: 200})
+			cfg.AddItem(cfgTenantIDFieldsOnly, ctypes.ConfigValueBool{Value: true})
+
+			Convey("Then configured options are used", func() {
+				opts := getListOpts(cfg)
+				So(opts.Limit, ShouldEqual, 200)
+				So(opts.Fields, ShouldResemble, []string{"id", "tenant_id"})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestQuotaUsage() {
+	Convey("Given quota usage of a tenant", s.T(), func() {
+
+		Convey("When quota is limited", func() {
+			So(quotaUtilization(5, 10), ShouldEqual, 0.5)
+			So(quotaHeadroom(5, 10), ShouldEqual, 5)
+		})
+
+		Convey("When quota is exceeded", func() {
+			So(quotaUtilization(12, 10), ShouldEqual, 1.2)
+			So(quotaHeadroom(12, 10), ShouldEqual, 0)
+		})
+
+		Convey("When quota is unlimited", func() {
+			So(quotaUtilization(5, unlimitedQuota), ShouldEqual, unlimitedQuota)
+			So(quotaHeadroom(5, unlimitedQuota), ShouldEqual, unlimitedQuota)
+		})
+
+		Convey("When quota is zero", func() {
+			So(quotaUtilization(0, 0), ShouldEqual, 0)
+			So(quotaUtilization(1, 0), ShouldEqual, 1)
+			So(quotaHeadroom(0, 0), ShouldEqual, 0)
+		})
+	})
+}
+
+func (s *TestSuite) TestGetCredentials() {
+	Convey("Given configuration with credentials", s.T(), func() {
+
+		Convey("When user, password and tenant are provided", func() {
+			cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+			creds, err := getCredentials(cfg)
+
+			Convey("Then password credentials are returned", func() {
+				So(err, ShouldBeNil)
+				So(creds.isApplicationCredential(), ShouldBeFalse)
+				So(creds.user, ShouldEqual, "admin")
+			})
+		})
+
+		Convey("When password is missing", func() {
+			cfg := setupCfg(th.Endpoint(), "admin", "", "")
+			_, err := getCredentials(cfg)
+
+			Convey("Then missing fields are reported", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, cfgPassword)
+				So(err.Error(), ShouldContainSubstring, cfgTenant)
+				So(err.Error(), ShouldNotContainSubstring, cfgUser)
+			})
+		})
+
+		Convey("When application credential ID and secret are provided", func() {
+			cfg := setupAppCredentialCfg(th.Endpoint(), "app-id", "", "secret", "")
+			creds, err := getCredentials(cfg)
+
+			Convey("Then application credentials are returned", func() {
+				So(err, ShouldBeNil)
+				So(creds.isApplicationCredential(), ShouldBeTrue)
+				So(creds.appCredentialID, ShouldEqual, "app-id")
+			})
+		})
+
+		Convey("When application credential name is provided without user and secret", func() {
+			cfg := setupAppCredentialCfg(th.Endpoint(), "", "app-name", "", "")
+			_, err := getCredentials(cfg)
+
+			Convey("Then missing fields are reported", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, cfgAppCredentialSecret)
+				So(err.Error(), ShouldContainSubstring, cfgUser)
+			})
+		})
+
+		Convey("When application credential secret is provided without ID or name", func() {
+			cfg := setupAppCredentialCfg(th.Endpoint(), "", "", "secret", "")
+			_, err := getCredentials(cfg)
+
+			Convey("Then missing fields are reported", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, cfgAppCredentialID+" or "+cfgAppCredentialName)
+			})
+		})
+
+		Convey("When both password and application credential are provided", func() {
+			cfg := setupCfg(th.Endpoint(), "admin", "secret", "admin")
+			cfg.AddItem(cfgAppCredentialID, ctypes.ConfigValueStr{Value: "app-id"})
+			cfg.AddItem(cfgAppCredentialSecret, ctypes.ConfigValueStr{Value: "secret"})
+			_, err := getCredentials(cfg)
+
+			Convey("Then error is reported", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestCredentialsRedaction() {
+	Convey("Given config with incorrect password", s.T(), func() {
+		password := "Sup3rS3cret!"
+		cfg := setupCfg(th.Endpoint(), "admin", password, "admin")
+
+		var logged bytes.Buffer
+		log.SetOutput(&logged)
+		defer log.SetOutput(os.Stderr)
+
+		Convey("When GetMetricTypes() is called", func() {
+			collector := New()
+			_, err := collector.GetMetricTypes(cfg)
+
+			Convey("Then error should be reported", func() {
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("and password should be masked in error fields", func() {
+				serr, ok := err.(serror.SnapError)
+				So(ok, ShouldBeTrue)
+				So(serr.Fields()["Password"], ShouldEqual, redact.Mask)
+				for _, v := range serr.Fields() {
+					So(v, ShouldNotEqual, password)
+				}
+			})
+
+			Convey("and password should not be logged", func() {
+				So(logged.String(), ShouldNotBeEmpty)
+				So(logged.String(), ShouldNotContainSubstring, password)
+			})
+		})
+
+		Convey("When CollectMetrics() is called", func() {
+			collector := New()
+			mTypes := []plugin.MetricType{
+				plugin.MetricType{Namespace_: core.NewNamespace(vendor, openstack, pluginName, "admin", networksCountMetric), Config_: cfg.ConfigDataNode},
+			}
+			_, err := collector.CollectMetrics(mTypes)
+
+			Convey("Then error should be reported", func() {
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("and password should not be logged", func() {
+				So(logged.String(), ShouldNotBeEmpty)
+				So(logged.String(), ShouldNotContainSubstring, password)
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetConfigPolicy() {
+	Convey("Meta should return metadata for the plugin", s.T(), func() {
+		meta := Meta()
+		So(meta.Name, ShouldResemble, pluginName)
+		So(meta.Version, ShouldResemble, version)
+		So(meta.Type, ShouldResemble, plugin.CollectorPluginType)
+	})
+
+	Convey("Given config with enpoint, user and password defined", s.T(), func() {
+		collector := New()
+
+		Convey("So collector should not be nil", func() {
+			So(collector, ShouldNotBeNil)
+		})
+
+		configPolicy, err := collector.GetConfigPolicy()
+
+		Convey("collector.GetConfigPolicy() should return a config policy", func() {
+			Convey("So config policy should not be nil", func() {
+				So(configPolicy, ShouldNotBeNil)
+			})
+
+			Convey("So we should not get an err retreiving the config policy", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("So config policy should be a cpolicy.ConfigPolicy", func() {
+				So(configPolicy, ShouldHaveSameTypeAs, &cpolicy.ConfigPolicy{})
+			})
+
+			correctConfig := make(map[string]ctypes.ConfigValue)
+			correctConfig[cfgURL] = ctypes.ConfigValueStr{Value: th.Endpoint()}
+			correctConfig[cfgUser] = ctypes.ConfigValueStr{Value: "admin"}
+			correctConfig[cfgPassword] = ctypes.ConfigValueStr{Value: "pass"}
+			correctConfig[cfgTenant] = ctypes.ConfigValueStr{Value: "admin"}
+
+			cfg, errs := configPolicy.Get([]string{""}).Process(correctConfig)
+
+			Convey("So config policy should process correctConfig and return a config", func() {
+				So(cfg, ShouldNotBeNil)
+			})
+
+			Convey("So correctConfig processing should return no errors", func() {
+				So(errs.HasErrors(), ShouldBeFalse)
+			})
+
+			appCredentialConfig := make(map[string]ctypes.ConfigValue)
+			appCredentialConfig[cfgURL] = ctypes.ConfigValueStr{Value: th.Endpoint()}
+			appCredentialConfig[cfgAppCredentialID] = ctypes.ConfigValueStr{Value: "app-id"}
+			appCredentialConfig[cfgAppCredentialSecret] = ctypes.ConfigValueStr{Value: "secret"}
+
+			cfgApp, errsApp := configPolicy.Get([]string{""}).Process(appCredentialConfig)
+
+			Convey("So config policy should process appCredentialConfig and return a config", func() {
+				So(cfgApp, ShouldNotBeNil)
+				So(errsApp.HasErrors(), ShouldBeFalse)
+			})
+
+			wrongConfig1 := make(map[string]ctypes.ConfigValue)
+
+			cfg1, errs1 := configPolicy.Get([]string{""}).Process(wrongConfig1)
+
+			Convey("So config policy should not return a config after processing wrongConfig1", func() {
+				So(cfg1, ShouldBeNil)
+			})
+
+			Convey("So wrongConfig1 processing should return no errors", func() {
+				So(errs1.HasErrors(), ShouldBeTrue)
+			})
+
+			wrongConfig2 := make(map[string]ctypes.ConfigValue)
+
+			cfg2, errs2 := configPolicy.Get([]string{""}).Process(wrongConfig2)
+
+			correctConfig[cfgURL] = ctypes.ConfigValueStr{Value: th.Endpoint()}
+			correctConfig[cfgUser] = ctypes.ConfigValueStr{Value: "admin"}
+
+			Convey("So config policy should not return a config after processing wrongConfig2", func() {
+				So(cfg2, ShouldBeNil)
+			})
+			Convey("So wrongConfig2 processing should return errors", func() {
+				So(errs2.HasErrors(), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func tenantNamespace(metricName string) core.Namespace {
+	return core.NewNamespace(vendor, openstack, pluginName).AddDynamicElement(tenantNameElement, "name of tenant").AddStaticElement(metricName)
+}
+
+func setupCfg(endpoint, user, password, tenant string) plugin.ConfigType {
+	node := cdata.NewNode()
+	node.AddItem(cfgURL, ctypes.ConfigValueStr{Value: endpoint})
+	node.AddItem(cfgUser, ctypes.ConfigValueStr{Value: user})
+	node.AddItem(cfgPassword, ctypes.ConfigValueStr{Value: password})
+	node.AddItem(cfgTenant, ctypes.ConfigValueStr{Value: tenant})
+	return plugin.ConfigType{ConfigDataNode: node}
+}
+
+func setupAppCredentialCfg(endpoint, credentialID, credentialName, secret, user string) plugin.ConfigType {
+	node := cdata.NewNode()
+	node.AddItem(cfgURL, ctypes.ConfigValueStr{Value: endpoint})
+	node.AddItem(cfgAppCredentialID, ctypes.ConfigValueStr{Value: credentialID})
+	node.AddItem(cfgAppCredentialName, ctypes.ConfigValueStr{Value: credentialName})
+	node.AddItem(cfgAppCredentialSecret, ctypes.ConfigValueStr{Value: secret})
+	node.AddItem(cfgUser, ctypes.ConfigValueStr{Value: user})
+	return plugin.ConfigType{ConfigDataNode: node}
+}
+
+func registerRoot() {
+	th.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `
+				{
+					"versions": {
+						"values": [
+							{
+								"status": "experimental",
+								"id": "v3.0",
+								"links": [
+									{ "href": "%s", "rel": "self" }
+								]
+							},
+							{
+								"status": "stable",
+								"id": "v2.0",
+								"links": [
+									{ "href": "%s", "rel": "self" }
+								]
+							}
+						]
+					}
+				}
+				`, th.Endpoint()+"v3/", th.Endpoint()+"v2.0/")
+	})
+}
+
+func registerAuthentication(s *TestSuite) {
+	s.Token = "cefb1b0ba45744488e6ed702db699327"
+	s.NetworkServiceEndpoint = th.Endpoint()
+	th.Mux.HandleFunc("/v2.0/tokens", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Auth struct {
+				PasswordCredentials struct {
+					Password string `json:"password"`
+				} `json:"passwordCredentials"`
+			} `json:"auth"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Auth.PasswordCredentials.Password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		octaviaService := ""
+		if s.OctaviaEndpoint != "" {
+			octaviaService = fmt.Sprintf(`,
+							{
+								"endpoints": [{"publicURL": "%s", "region": "RegionOne"}],
+								"endpoints_links": [],
+								"name": "octavia",
+								"type": "load-balancer"
+							}`, s.OctaviaEndpoint)
+		}
+
+		fmt.Fprintf(w, `
+				{
+					"access": {
+						"metadata": {
+							"is_admin": 0,
+							"roles": [
+								"dc6ed0c1bfb847c9b087e1d62068766b"
+							]
+						},
+						"serviceCatalog": [
+						{
+								"endpoints": [
+									{
+										"adminURL": "%s",
+										"id": "3ffe125aa59547029ed774c10b932349",
+										"internalURL": "%s",
+										"publicURL": "%s",
+										"region": "RegionOne"
+									}
+								],
+								"endpoints_links": [],
+								"name": "neutron",
+								"type": "network"
+							}%s
+						],
+						"token": {
+							"expires": "2017-01-19T15:33:11Z",
+							"id": "%s",
+							"issued_at": "2017-01-19T14:33:11.541197Z",
+							"tenant": {
+								"description": "",
+								"enabled": true,
+								"id": "444f244be5e34ce0816e8beccfd332ef",
+								"name": "tenant"
+							}
+						}
+					}
+				}
+			`, s.NetworkServiceEndpoint,
+			s.NetworkServiceEndpoint,
+			s.NetworkServiceEndpoint,
+			octaviaService,
+			s.Token)
+	})
+}
+
+func registerTenants(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/tenants", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"tenants": [
+					{
+						"description": "demo tenat",
+						"enabled": true,
+						"id": "111111",
+						"name": "demo"
+					},
+					{
+						"description": "admin tenant",
+						"enabled": true,
+						"id": "222222",
+						"name": "admin"
+					}
+				],
+				"tenants_links": []
+			}
+		`)
+	})
+}
+
+func registerNetworks(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/networks", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+			  "networks": [
+			    {
+			      "admin_state_up": true,
+			      "availability_zone_hints": [],
+			      "availability_zones": [
+				"nova"
+			      ],
+			      "created_at": "2016-09-08T12:01:34",
+			      "description": "",
+			      "id": "28dd974d-0ec0-43cc-86ac-06773acb126f",
+			      "ipv4_address_scope": null,
+			      "ipv6_address_scope": null,
+			      "mtu": 1450,
+			      "name": "private",
+			      "port_security_enabled": true,
+			      "provider:network_type": "vxlan",
+			      "provider:physical_network": null,
+			      "provider:segmentation_id": 19,
+			      "revision": 6,
+			      "router:external": false,
+			      "shared": false,
+			      "status": "ACTIVE",
+			      "subnets": [
+				"ef512c07-9203-4121-9df6-e692a4bc84c5",
+				"64c8fbe0-cb8a-41d7-9e65-56f33f9674cb"
+			      ],
+			      "tags": [],
+			      "tenant_id": "111111",
+			      "updated_at": "2016-09-08T12:01:38"
+			    },
+			    {
+			      "admin_state_up": true,
+			      "availability_zone_hints": [],
+			      "availability_zones": [
+				"nova"
+			      ],
+			      "created_at": "2016-09-08T12:01:51",
+			      "description": "",
+			      "id": "f3722668-e9e7-41dd-8086-5e1b9f5d8209",
+			      "ipv4_address_scope": null,
+			      "ipv6_address_scope": null,
+			      "is_default": true,
+			      "mtu": 1500,
+			      "name": "public",
+			      "port_security_enabled": true,
+			      "provider:network_type": "flat",
+			      "provider:physical_network": "public",
+			      "provider:segmentation_id": null,
+			      "qos_policy_id": "e8f1b9c6-2b6a-4d1e-9a43-2f9e0c1d5a77",
+			      "revision": 6,
+			      "router:external": true,
+			      "shared": false,
+			      "status": "ACTIVE",
+			      "subnets": [
+				"4582d819-7ded-4ec5-aa92-27f73781f625",
+				"94daf3aa-6faf-43c0-a21c-9656110b3d11"
+			      ],
+			      "tags": [],
+			      "tenant_id": "222222",
+			      "updated_at": "2016-09-08T12:02:05"
+			    },
+			    {
+			      "admin_state_up": true,
+			      "availability_zone_hints": [],
+			      "availability_zones": [
+				"nova"
+			      ],
+			      "created_at": "2016-09-08T12:01:51",
+			      "description": "",
+			      "id": "f3722668-e9e7-41dd-8086-5e1b9f5d8209",
+			      "ipv4_address_scope": null,
+			      "ipv6_address_scope": null,
+			      "is_default": true,
+			      "mtu": 1500,
+			      "name": "public",
+			      "port_security_enabled": true,
+			      "provider:network_type": "flat",
+			      "provider:physical_network": "public",
+			      "provider:segmentation_id": null,
+			      "revision": 6,
+			      "router:external": true,
+			      "shared": false,
+			      "status": "ACTIVE",
+			      "subnets": [
+				"4582d819-7ded-4ec5-aa92-27f73781f625",
+				"94daf3aa-6faf-43c0-a21c-9656110b3d11"
+			      ],
+			      "tags": [],
+			      "tenant_id": "222222",
+			      "updated_at": "2016-09-08T12:02:05"
+			    }
+			  ]
+			}
+		`)
+	})
+}
+
+func registerSubnets(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/subnets", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+			  "subnets": [
+			    {
+			      "allocation_pools": [
+				{
+				  "end": "2001:db8::1",
+				  "start": "2001:db8::1"
+				},
+				{
+				  "end": "2001:db8::ffff:ffff:ffff:ffff",
+				  "start": "2001:db8::3"
+				}
+			      ],
+			      "cidr": "2001:db8::/64",
+			      "created_at": "2016-09-08T12:02:05",
+			      "description": "",
+			      "dns_nameservers": [],
 			      "enable_dhcp": false,
 			      "gateway_ip": "2001:db8::2",
 			      "host_routes": [],
@@ -793,334 +2447,837 @@ func registerSubnets(s *TestSuite) {
 			      "updated_at": "2016-09-08T12:02:05"
 			    },
 			    {
-			      "allocation_pools": [
+			      "allocation_pools": [
+				{
+				  "end": "10.0.0.254",
+				  "start": "10.0.0.2"
+				}
+			      ],
+			      "cidr": "10.0.0.0/24",
+			      "created_at": "2016-09-08T12:01:36",
+			      "description": "",
+			      "dns_nameservers": [],
+			      "enable_dhcp": true,
+			      "gateway_ip": "10.0.0.1",
+			      "host_routes": [],
+			      "id": "64c8fbe0-cb8a-41d7-9e65-56f33f9674cb",
+			      "ip_version": 4,
+			      "ipv6_address_mode": null,
+			      "ipv6_ra_mode": null,
+			      "name": "private-subnet",
+			      "network_id": "28dd974d-0ec0-43cc-86ac-06773acb126f",
+			      "revision": 2,
+			      "service_types": [],
+			      "subnetpool_id": "f49a1319-423a-4ee6-ba54-1d95a4f6cc68",
+			      "tenant_id": "222222",
+			      "updated_at": "2016-09-08T12:01:36"
+			    },
+			    {
+			      "allocation_pools": [
+				{
+				  "end": "172.24.4.254",
+				  "start": "172.24.4.2"
+				}
+			      ],
+			      "cidr": "172.24.4.0/24",
+			      "created_at": "2016-09-08T12:01:56",
+			      "description": "",
+			      "dns_nameservers": [],
+			      "enable_dhcp": false,
+			      "gateway_ip": "172.24.4.1",
+			      "host_routes": [],
+			      "id": "94daf3aa-6faf-43c0-a21c-9656110b3d11",
+			      "ip_version": 4,
+			      "ipv6_address_mode": null,
+			      "ipv6_ra_mode": null,
+			      "name": "public-subnet",
+			      "network_id": "f3722668-e9e7-41dd-8086-5e1b9f5d8209",
+			      "revision": 2,
+			      "service_types": [],
+			      "subnetpool_id": null,
+			      "tenant_id": "222222",
+			      "updated_at": "2016-09-08T12:01:56"
+			    }
+			  ]
+			}
+        `)
+	})
+}
+
+func registerRouters(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/routers", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+			  "routers": [
+			    {
+			      "admin_state_up": true,
+			      "availability_zone_hints": [],
+			      "availability_zones": [
+				"nova"
+			      ],
+			      "description": "",
+			      "distributed": false,
+			      "external_gateway_info": {
+				"enable_snat": true,
+				"external_fixed_ips": [
+				  {
+				    "ip_address": "172.24.4.3",
+				    "subnet_id": "94daf3aa-6faf-43c0-a21c-9656110b3d11"
+				  },
+				  {
+				    "ip_address": "2001:db8::1",
+				    "subnet_id": "4582d819-7ded-4ec5-aa92-27f73781f625"
+				  }
+				],
+				"network_id": "f3722668-e9e7-41dd-8086-5e1b9f5d8209"
+			      },
+			      "flavor_id": null,
+			      "ha": false,
+			      "id": "a75c645a-6dcc-418c-9371-9be7054c395e",
+			      "name": "router1",
+			      "revision": 8,
+			      "routes": [],
+			      "status": "ACTIVE",
+			      "tenant_id": "222222"
+			    },
+			    {
+			      "admin_state_up": true,
+			      "availability_zone_hints": [],
+			      "availability_zones": [
+				"nova"
+			      ],
+			      "description": "",
+			      "distributed": false,
+			      "external_gateway_info": {
+				"enable_snat": true,
+				"external_fixed_ips": [
+				  {
+				    "ip_address": "172.24.4.3",
+				    "subnet_id": "94daf3aa-6faf-43c0-a21c-9656110b3d11"
+				  },
+				  {
+				    "ip_address": "2001:db8::1",
+				    "subnet_id": "4582d819-7ded-4ec5-aa92-27f73781f625"
+				  }
+				],
+				"network_id": "f3722668-e9e7-41dd-8086-5e1b9f5d8209"
+			      },
+			      "flavor_id": null,
+			      "ha": false,
+			      "id": "a75c645a-6dcc-418c-9371-9be7054c395e",
+			      "name": "router1",
+			      "revision": 8,
+			      "routes": [],
+			      "status": "ACTIVE",
+			      "tenant_id": "222222"
+			    },
+			    {
+			      "admin_state_up": true,
+			      "availability_zone_hints": [],
+			      "availability_zones": [
+				"nova"
+			      ],
+			      "description": "",
+			      "distributed": false,
+			      "external_gateway_info": {
+				"enable_snat": true,
+				"external_fixed_ips": [
+				  {
+				    "ip_address": "172.24.4.3",
+				    "subnet_id": "94daf3aa-6faf-43c0-a21c-9656110b3d11"
+				  },
+				  {
+				    "ip_address": "2001:db8::1",
+				    "subnet_id": "4582d819-7ded-4ec5-aa92-27f73781f625"
+				  }
+				],
+				"network_id": "f3722668-e9e7-41dd-8086-5e1b9f5d8209"
+			      },
+			      "flavor_id": null,
+			      "ha": false,
+			      "id": "a75c645a-6dcc-418c-9371-9be7054c395e",
+			      "name": "router1",
+			      "revision": 8,
+			      "routes": [],
+			      "status": "ACTIVE",
+			      "tenant_id": "222222"
+			    },
+			    {
+			      "admin_state_up": true,
+			      "availability_zone_hints": [],
+			      "availability_zones": [
+				"nova"
+			      ],
+			      "description": "",
+			      "distributed": false,
+			      "external_gateway_info": {
+				"enable_snat": true,
+				"external_fixed_ips": [
+				  {
+				    "ip_address": "172.24.4.3",
+				    "subnet_id": "94daf3aa-6faf-43c0-a21c-9656110b3d11"
+				  },
+				  {
+				    "ip_address": "2001:db8::1",
+				    "subnet_id": "4582d819-7ded-4ec5-aa92-27f73781f625"
+				  }
+				],
+				"network_id": "f3722668-e9e7-41dd-8086-5e1b9f5d8209"
+			      },
+			      "flavor_id": null,
+			      "ha": false,
+			      "id": "a75c645a-6dcc-418c-9371-9be7054c395e",
+			      "name": "router1",
+			      "revision": 8,
+			      "routes": [],
+			      "status": "ACTIVE",
+			      "tenant_id": "222222"
+			    }
+			  ]
+			}
+		`)
+	})
+}
+
+func registerPorts(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/ports", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+			  "ports": [
+			    {
+			      "admin_state_up": true,
+			      "allowed_address_pairs": [],
+			      "binding:host_id": "es-051",
+			      "binding:profile": {},
+			      "binding:vif_details": {
+				"ovs_hybrid_plug": true,
+				"port_filter": true
+			      },
+			      "binding:vif_type": "ovs",
+			      "binding:vnic_type": "normal",
+			      "created_at": "2016-10-20T13:07:02",
+			      "description": "",
+			      "device_id": "dd5dfb24-0e5d-41b1-a4a8-4693906d5f5b",
+			      "device_owner": "compute:nova",
+			      "extra_dhcp_opts": [],
+			      "fixed_ips": [
 				{
-				  "end": "10.0.0.254",
-				  "start": "10.0.0.2"
+				  "ip_address": "fdaf:f360:d434:0:f816:3eff:fe3b:fe08",
+				  "subnet_id": "ef512c07-9203-4121-9df6-e692a4bc84c5"
+				},
+				{
+				  "ip_address": "10.0.0.5",
+				  "subnet_id": "64c8fbe0-cb8a-41d7-9e65-56f33f9674cb"
 				}
 			      ],
-			      "cidr": "10.0.0.0/24",
-			      "created_at": "2016-09-08T12:01:36",
+			      "id": "004e9c25-de09-4d4c-a2c3-50b05defaac9",
+			      "mac_address": "fa:16:3e:3b:fe:08",
+			      "name": "",
+			      "network_id": "28dd974d-0ec0-43cc-86ac-06773acb126f",
+			      "port_security_enabled": true,
+			      "qos_policy_id": "e8f1b9c6-2b6a-4d1e-9a43-2f9e0c1d5a77",
+			      "revision": 10,
+			      "security_groups": [
+				"f47fd611-39d9-4999-9b10-41b19e03d40a"
+			      ],
+			      "status": "ACTIVE",
+			      "tenant_id": "222222",
+			      "updated_at": "2016-10-20T13:07:25"
+			    },
+			    {
+			      "admin_state_up": true,
+			      "allowed_address_pairs": [],
+			      "binding:host_id": "es-051",
+			      "binding:profile": {},
+			      "binding:vif_details": {
+				"ovs_hybrid_plug": true,
+				"port_filter": true
+			      },
+			      "binding:vif_type": "ovs",
+			      "binding:vnic_type": "normal",
+			      "created_at": "2016-10-20T13:07:01",
 			      "description": "",
-			      "dns_nameservers": [],
-			      "enable_dhcp": true,
-			      "gateway_ip": "10.0.0.1",
-			      "host_routes": [],
-			      "id": "64c8fbe0-cb8a-41d7-9e65-56f33f9674cb",
-			      "ip_version": 4,
-			      "ipv6_address_mode": null,
-			      "ipv6_ra_mode": null,
-			      "name": "private-subnet",
+			      "device_id": "92fcd563-3605-4cfa-9241-520566d79e68",
+			      "device_owner": "network:dhcp",
+			      "extra_dhcp_opts": [],
+			      "fixed_ips": [
+				{
+				  "ip_address": "fdaf:f360:d434:0:f816:3eff:fe17:68b3",
+				  "subnet_id": "ef512c07-9203-4121-9df6-e692a4bc84c5"
+				},
+				{
+				  "ip_address": "10.0.0.7",
+				  "subnet_id": "64c8fbe0-cb8a-41d7-9e65-56f33f9674cb"
+				}
+			      ],
+			      "id": "0a3bdc80-5b3e-4fca-baca-9716d94f56b5",
+			      "mac_address": "fa:16:3e:17:68:b3",
+			      "name": "",
 			      "network_id": "28dd974d-0ec0-43cc-86ac-06773acb126f",
-			      "revision": 2,
-			      "service_types": [],
-			      "subnetpool_id": null,
+			      "port_security_enabled": true,
+			      "revision": 8,
+			      "security_groups": [
+				"f47fd611-39d9-4999-9b10-41b19e03d40a"
+			      ],
+			      "status": "DOWN",
 			      "tenant_id": "222222",
-			      "updated_at": "2016-09-08T12:01:36"
+			      "updated_at": "2016-10-20T13:07:23"
 			    },
 			    {
-			      "allocation_pools": [
+			      "admin_state_up": true,
+			      "allowed_address_pairs": [],
+			      "binding:host_id": "es-051",
+			      "binding:profile": {},
+			      "binding:vif_details": {
+				"ovs_hybrid_plug": true,
+				"port_filter": true
+			      },
+			      "binding:vif_type": "ovs",
+			      "binding:vnic_type": "normal",
+			      "created_at": "2016-09-08T12:01:54",
+			      "description": "",
+			      "device_id": "a75c645a-6dcc-418c-9371-9be7054c395e",
+			      "device_owner": "network:router_interface",
+			      "extra_dhcp_opts": [],
+			      "fixed_ips": [
+				{
+				  "ip_address": "10.0.0.1",
+				  "subnet_id": "64c8fbe0-cb8a-41d7-9e65-56f33f9674cb"
+				}
+			      ],
+			      "id": "11bc164c-c2dd-4809-9b04-0ef4aaefd8a2",
+			      "mac_address": "fa:16:3e:da:6a:9a",
+			      "name": "",
+			      "network_id": "28dd974d-0ec0-43cc-86ac-06773acb126f",
+			      "port_security_enabled": false,
+			      "revision": 8,
+			      "security_groups": [],
+			      "status": "ACTIVE",
+			      "tenant_id": "222222",
+			      "updated_at": "2016-10-20T13:07:23"
+			    }
+			  ]
+			}
+      `)
+	})
+}
+
+func registerFloatingIPs(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/floatingips", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		if s.FloatingIPsUnavailable {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+		{
+			"floatingips": [
+				{
+					"floating_network_id": "28dc974d-0ec0-43cc-86ac-06773acb126f",
+					"router_id": null,
+					"fixed_ip_address": null,
+					"floating_ip_address": "192.0.0.4",
+					"tenant_id": "222222",
+					"status": "DOWN",
+					"port_id": null,
+					"id": "a75c645a-6dcd-418c-9371-9be7054c395e"
+				},
+				{
+					"floating_network_id": "ef51217-9203-4121-9df6-e692a4bc84c5",
+					"router_id": "0a24cb83-faf5-4d7f-b723-3144ed8a2167",
+					"fixed_ip_address": "192.0.0.2",
+					"floating_ip_address": "10.0.0.3",
+					"tenant_id": "222222",
+					"status": "ACTIVE",
+					"port_id": "004e9c25-de19-4d4c-a2c3-50b05defaac9",
+					"id": "bfdd31f8-ccda-4722-9319-13a7138e226c"
+				},
+				{
+					"floating_network_id": "28dc974d-0ec0-43cc-86ac-06773acb126f",
+					"router_id": null,
+					"fixed_ip_address": null,
+					"floating_ip_address": "192.0.0.9",
+					"tenant_id": "999999",
+					"status": "DOWN",
+					"port_id": null,
+					"id": "61cea855-49cb-4846-997d-801b70c71bdd"
+				}
+			]
+		}
+		`)
+	})
+}
+
+func registerIPAvailabilities(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/network-ip-availabilities", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		if s.IPAvailabilityStatus != 0 {
+			w.WriteHeader(s.IPAvailabilityStatus)
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+		{
+			"network_ip_availabilities": [
 				{
-				  "end": "172.24.4.254",
-				  "start": "172.24.4.2"
+					"network_id": "f3722668-e9e7-41dd-8086-5e1b9f5d8209",
+					"network_name": "public",
+					"tenant_id": "222222",
+					"total_ips": 253,
+					"used_ips": 12,
+					"subnet_ip_availability": [
+						{
+							"subnet_id": "94daf3aa-6faf-43c0-a21c-9656110b3d11",
+							"subnet_name": "public-subnet",
+							"cidr": "172.24.4.0/24",
+							"ip_version": 4,
+							"total_ips": 253,
+							"used_ips": 12
+						}
+					]
+				},
+				{
+					"network_id": "28dd974d-0ec0-43cc-86ac-06773acb126f",
+					"network_name": "private",
+					"tenant_id": "111111",
+					"total_ips": 253,
+					"used_ips": 3,
+					"subnet_ip_availability": [
+						{
+							"subnet_id": "64c8fbe0-cb8a-41d7-9e65-56f33f9674cb",
+							"subnet_name": "private-subnet",
+							"cidr": "10.0.0.0/24",
+							"ip_version": 4,
+							"total_ips": 253,
+							"used_ips": 3
+						}
+					]
 				}
-			      ],
-			      "cidr": "172.24.4.0/24",
-			      "created_at": "2016-09-08T12:01:56",
-			      "description": "",
-			      "dns_nameservers": [],
-			      "enable_dhcp": false,
-			      "gateway_ip": "172.24.4.1",
-			      "host_routes": [],
-			      "id": "94daf3aa-6faf-43c0-a21c-9656110b3d11",
-			      "ip_version": 4,
-			      "ipv6_address_mode": null,
-			      "ipv6_ra_mode": null,
-			      "name": "public-subnet",
-			      "network_id": "f3722668-e9e7-41dd-8086-5e1b9f5d8209",
-			      "revision": 2,
-			      "service_types": [],
-			      "subnetpool_id": null,
-			      "tenant_id": "222222",
-			      "updated_at": "2016-09-08T12:01:56"
-			    }
-			  ]
-			}
-        `)
+			]
+		}
+		`)
 	})
 }
 
-func registerRouters(s *TestSuite) {
-	th.Mux.HandleFunc("/v2.0/routers", func(w http.ResponseWriter, r *http.Request) {
+func registerAgents(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/agents", func(w http.ResponseWriter, r *http.Request) {
 		th.TestMethod(s.T(), r, "GET")
 		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
 
 		w.Header().Add("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 
+		now := time.Now().UTC()
 		fmt.Fprintf(w, `
+		{
+			"agents": [
+				{
+					"id": "04c62b91-b799-48b7-9cd5-2982db6df9c6",
+					"agent_type": "L3 agent",
+					"binary": "neutron-l3-agent",
+					"host": "network-1",
+					"availability_zone": "nova",
+					"alive": true,
+					"admin_state_up": true,
+					"heartbeat_timestamp": "%s"
+				},
+				{
+					"id": "840d5d68-5759-4e9e-812f-f3bd19214c7f",
+					"agent_type": "DHCP agent",
+					"binary": "neutron-dhcp-agent",
+					"host": "network-1",
+					"availability_zone": "nova",
+					"alive": false,
+					"admin_state_up": true,
+					"heartbeat_timestamp": "%s"
+				},
+				{
+					"id": "9c7b2d7a-3ed4-4f3c-9c5e-2d1a4a3c7a11",
+					"agent_type": "Open vSwitch agent",
+					"binary": "neutron-openvswitch-agent",
+					"host": "compute-1",
+					"availability_zone": null,
+					"alive": true,
+					"admin_state_up": false,
+					"heartbeat_timestamp": "%s"
+				}
+			]
+		}
+		`, now.Format("2006-01-02 15:04:05"), now.Add(-time.Hour).Format("2006-01-02 15:04:05"), now.Format("2006-01-02 15:04:05"))
+	})
+}
+
+func registerLoadBalancers(s *TestSuite) {
+	// the same resources are served by Neutron LBaaS v2 extension and by Octavia
+	for _, prefix := range []string{"/v2.0/", "/octavia/v2/"} {
+		th.Mux.HandleFunc(prefix+"lbaas/loadbalancers", func(w http.ResponseWriter, r *http.Request) {
+			th.TestMethod(s.T(), r, "GET")
+			th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+
+			fmt.Fprintf(w, `
 			{
-			  "routers": [
-			    {
-			      "admin_state_up": true,
-			      "availability_zone_hints": [],
-			      "availability_zones": [
-				"nova"
-			      ],
-			      "description": "",
-			      "distributed": false,
-			      "external_gateway_info": {
-				"enable_snat": true,
-				"external_fixed_ips": [
-				  {
-				    "ip_address": "172.24.4.3",
-				    "subnet_id": "94daf3aa-6faf-43c0-a21c-9656110b3d11"
-				  },
-				  {
-				    "ip_address": "2001:db8::1",
-				    "subnet_id": "4582d819-7ded-4ec5-aa92-27f73781f625"
-				  }
-				],
-				"network_id": "f3722668-e9e7-41dd-8086-5e1b9f5d8209"
-			      },
-			      "flavor_id": null,
-			      "ha": false,
-			      "id": "a75c645a-6dcc-418c-9371-9be7054c395e",
-			      "name": "router1",
-			      "revision": 8,
-			      "routes": [],
-			      "status": "ACTIVE",
-			      "tenant_id": "222222"
-			    },
-			    {
-			      "admin_state_up": true,
-			      "availability_zone_hints": [],
-			      "availability_zones": [
-				"nova"
-			      ],
-			      "description": "",
-			      "distributed": false,
-			      "external_gateway_info": {
-				"enable_snat": true,
-				"external_fixed_ips": [
-				  {
-				    "ip_address": "172.24.4.3",
-				    "subnet_id": "94daf3aa-6faf-43c0-a21c-9656110b3d11"
-				  },
-				  {
-				    "ip_address": "2001:db8::1",
-				    "subnet_id": "4582d819-7ded-4ec5-aa92-27f73781f625"
-				  }
-				],
-				"network_id": "f3722668-e9e7-41dd-8086-5e1b9f5d8209"
-			      },
-			      "flavor_id": null,
-			      "ha": false,
-			      "id": "a75c645a-6dcc-418c-9371-9be7054c395e",
-			      "name": "router1",
-			      "revision": 8,
-			      "routes": [],
-			      "status": "ACTIVE",
-			      "tenant_id": "222222"
-			    },
-			    {
-			      "admin_state_up": true,
-			      "availability_zone_hints": [],
-			      "availability_zones": [
-				"nova"
-			      ],
-			      "description": "",
-			      "distributed": false,
-			      "external_gateway_info": {
-				"enable_snat": true,
-				"external_fixed_ips": [
-				  {
-				    "ip_address": "172.24.4.3",
-				    "subnet_id": "94daf3aa-6faf-43c0-a21c-9656110b3d11"
-				  },
-				  {
-				    "ip_address": "2001:db8::1",
-				    "subnet_id": "4582d819-7ded-4ec5-aa92-27f73781f625"
-				  }
-				],
-				"network_id": "f3722668-e9e7-41dd-8086-5e1b9f5d8209"
-			      },
-			      "flavor_id": null,
-			      "ha": false,
-			      "id": "a75c645a-6dcc-418c-9371-9be7054c395e",
-			      "name": "router1",
-			      "revision": 8,
-			      "routes": [],
-			      "status": "ACTIVE",
-			      "tenant_id": "222222"
-			    },
-			    {
-			      "admin_state_up": true,
-			      "availability_zone_hints": [],
-			      "availability_zones": [
-				"nova"
-			      ],
-			      "description": "",
-			      "distributed": false,
-			      "external_gateway_info": {
-				"enable_snat": true,
-				"external_fixed_ips": [
-				  {
-				    "ip_address": "172.24.4.3",
-				    "subnet_id": "94daf3aa-6faf-43c0-a21c-9656110b3d11"
-				  },
-				  {
-				    "ip_address": "2001:db8::1",
-				    "subnet_id": "4582d819-7ded-4ec5-aa92-27f73781f625"
-				  }
-				],
-				"network_id": "f3722668-e9e7-41dd-8086-5e1b9f5d8209"
-			      },
-			      "flavor_id": null,
-			      "ha": false,
-			      "id": "a75c645a-6dcc-418c-9371-9be7054c395e",
-			      "name": "router1",
-			      "revision": 8,
-			      "routes": [],
-			      "status": "ACTIVE",
-			      "tenant_id": "222222"
-			    }
-			  ]
+				"loadbalancers": [
+					{
+						"id": "a36c20d0-18e9-42ce-88fd-82a35977ee8c",
+						"tenant_id": "222222",
+						"provisioning_status": "ACTIVE",
+						"operating_status": "ONLINE"
+					},
+					{
+						"id": "607226db-27ef-4d41-ae89-f2a800e9c2db",
+						"tenant_id": "111111",
+						"provisioning_status": "PENDING_UPDATE",
+						"operating_status": "ONLINE"
+					}
+				]
+			}
+			`)
+		})
+		th.Mux.HandleFunc(prefix+"lbaas/listeners", func(w http.ResponseWriter, r *http.Request) {
+			th.TestMethod(s.T(), r, "GET")
+			th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+
+			fmt.Fprintf(w, `
+			{
+				"listeners": [
+					{
+						"id": "023f2e34-7806-443b-bfae-16c324569a3d",
+						"tenant_id": "222222",
+						"provisioning_status": "ACTIVE",
+						"operating_status": "ONLINE"
+					}
+				]
+			}
+			`)
+		})
+		th.Mux.HandleFunc(prefix+"lbaas/pools", func(w http.ResponseWriter, r *http.Request) {
+			th.TestMethod(s.T(), r, "GET")
+			th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+
+			fmt.Fprintf(w, `
+			{
+				"pools": [
+					{
+						"id": "4c0a0a5f-cf8f-44b7-b912-957daa8ce5e5",
+						"tenant_id": "222222",
+						"provisioning_status": "ACTIVE",
+						"operating_status": "DEGRADED",
+						"members": [
+							{"id": "957a1ace-1bd2-449b-8455-820b6e4b63f3"},
+							{"id": "fcf23bde-8cf9-4616-883f-208cebcbf858"}
+						]
+					},
+					{
+						"id": "d7fe2a5a-9c52-4de9-a3dc-4e4d0a3c9a52",
+						"tenant_id": "222222",
+						"provisioning_status": "ACTIVE",
+						"operating_status": "ONLINE",
+						"members": []
+					}
+				]
+			}
+			`)
+		})
+		th.Mux.HandleFunc(prefix+"lbaas/pools/4c0a0a5f-cf8f-44b7-b912-957daa8ce5e5/members", func(w http.ResponseWriter, r *http.Request) {
+			th.TestMethod(s.T(), r, "GET")
+			th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+
+			fmt.Fprintf(w, `
+			{
+				"members": [
+					{
+						"id": "957a1ace-1bd2-449b-8455-820b6e4b63f3",
+						"project_id": "222222",
+						"provisioning_status": "ACTIVE",
+						"operating_status": "ONLINE"
+					},
+					{
+						"id": "fcf23bde-8cf9-4616-883f-208cebcbf858",
+						"project_id": "222222",
+						"provisioning_status": "ACTIVE",
+						"operating_status": "ERROR"
+					}
+				]
+			}
+			`)
+		})
+		th.Mux.HandleFunc(prefix+"lbaas/healthmonitors", func(w http.ResponseWriter, r *http.Request) {
+			th.TestMethod(s.T(), r, "GET")
+			th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+
+			fmt.Fprintf(w, `
+			{
+				"healthmonitors": [
+					{
+						"id": "8ed3c5ac-6302-4f7e-85b4-a6c2d5cfd3d6",
+						"tenant_id": "222222",
+						"provisioning_status": "ACTIVE",
+						"operating_status": "ONLINE"
+					}
+				]
+			}
+			`)
+		})
+	}
+}
+
+func registerVPN(s *TestSuite) {
+	register := func(path, body string) {
+		th.Mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			th.TestMethod(s.T(), r, "GET")
+			th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+
+			fmt.Fprint(w, body)
+		})
+	}
+
+	register("/v2.0/vpn/ikepolicies", `
+		{
+			"ikepolicies": [
+				{"id": "5522aff7-1b3c-48dd-9c3c-b50f016b73db", "tenant_id": "222222", "name": "ikepolicy1"}
+			]
+		}
+	`)
+	register("/v2.0/vpn/ipsecpolicies", `
+		{
+			"ipsecpolicies": [
+				{"id": "5291b189-fd84-46e5-84bd-78f40c05d69c", "tenant_id": "222222", "name": "ipsecpolicy1"},
+				{"id": "9958d4fe-3719-4e8c-84e7-9893895b76b4", "tenant_id": "222222", "name": "ipsecpolicy2"}
+			]
+		}
+	`)
+	register("/v2.0/vpn/vpnservices", `
+		{
+			"vpnservices": [
+				{"id": "9faaf49f-dd89-4e39-a8c6-101839aa49bc", "tenant_id": "222222", "status": "ACTIVE"}
+			]
+		}
+	`)
+	register("/v2.0/vpn/ipsec-site-connections", `
+		{
+			"ipsec_site_connections": [
+				{"id": "851f280f-5639-4ea3-81aa-e298525ab74b", "tenant_id": "222222", "status": "ACTIVE"},
+				{"id": "f7b1f5a5-2f85-4fb1-a1b8-10e8f1c4ca01", "tenant_id": "222222", "status": "DOWN"},
+				{"id": "3e3d9f0d-7b6b-4c7a-9c2e-9b7c2c0e7d14", "tenant_id": "222222", "status": "PENDING_CREATE"},
+				{"id": "0c4b4a4e-44a2-4b0e-9a3a-3c3a6a7b1f2e", "tenant_id": "111111", "status": "ERROR"}
+			]
+		}
+	`)
+}
+
+func registerRBACPoliciesAndSubnetPools(s *TestSuite) {
+	register := func(path, body string) {
+		th.Mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			th.TestMethod(s.T(), r, "GET")
+			th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+
+			fmt.Fprint(w, body)
+		})
+	}
+
+	register("/v2.0/rbac-policies", `
+		{
+			"rbac_policies": [
+				{"id": "f8ecf7b3-4b3a-4f4a-b4b7-b1e5c3f1ad4e", "tenant_id": "222222", "object_type": "network", "action": "access_as_shared"},
+				{"id": "2cf7523a-93b5-4e69-9360-6c6bf986bb7c", "tenant_id": "222222", "object_type": "network", "action": "access_as_external"},
+				{"id": "7a1b1f4c-0f66-4d5d-8d4f-2c1f1a4f5a0e", "tenant_id": "222222", "object_type": "qos_policy", "action": "access_as_shared"},
+				{"id": "c9b9e5a4-6f4e-44ab-9a8c-33c4b3a1d7f2", "tenant_id": "111111", "object_type": "security_group", "action": "access_as_shared"}
+			]
+		}
+	`)
+	register("/v2.0/subnetpools", `
+		{
+			"subnetpools": [
+				{"id": "f49a1319-423a-4ee6-ba54-1d95a4f6cc68", "tenant_id": "222222", "name": "pool-v4", "prefixes": ["10.0.0.0/16"], "ip_version": 4, "shared": false},
+				{"id": "2fe7a1ae-6fa6-4b2b-8d2f-e8f5d0b1c9a3", "tenant_id": "111111", "name": "pool-shared", "prefixes": ["192.168.0.0/24", "192.168.1.0/24"], "ip_version": 4, "shared": true}
+			]
+		}
+	`)
+}
+
+func registerQoSPolicies(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/qos/policies", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"policies": [
+					{
+						"id": "e8f1b9c6-2b6a-4d1e-9a43-2f9e0c1d5a77",
+						"tenant_id": "222222",
+						"name": "bw-limiter",
+						"rules": [
+							{"id": "5f126d84-551a-4dcf-bb01-0e9c0df0c793", "type": "bandwidth_limit", "max_kbps": 10000, "direction": "egress"},
+							{"id": "a1b2c3d4-5e6f-4a7b-8c9d-0e1f2a3b4c5d", "type": "bandwidth_limit", "max_kbps": 10000, "direction": "ingress"},
+							{"id": "8a7c6f9e-3b1d-4e2f-a5c4-9d8e7f6a5b4c", "type": "dscp_marking", "dscp_mark": 26}
+						]
+					},
+					{"id": "3c1d5e7f-9a2b-4c6d-8e0f-1a2b3c4d5e6f", "tenant_id": "222222", "name": "empty", "rules": []},
+					{
+						"id": "b5a4c3d2-e1f0-4a9b-8c7d-6e5f4a3b2c1d",
+						"tenant_id": "111111",
+						"name": "guaranteed",
+						"rules": [
+							{"id": "1e2d3c4b-5a69-4788-9a0b-c1d2e3f4a5b6", "type": "minimum_bandwidth", "min_kbps": 1000}
+						]
+					}
+				]
 			}
 		`)
 	})
 }
 
-func registerPorts(s *TestSuite) {
-	th.Mux.HandleFunc("/v2.0/ports", func(w http.ResponseWriter, r *http.Request) {
+func registerLoadBalancerQuotas(s *TestSuite) {
+	for tenantID, loadBalancers := range map[string]int{"222222": 5, "111111": 2} {
+		body := fmt.Sprintf(`
+			{
+				"quota": {
+					"load_balancer": %d,
+					"listener": -1,
+					"pool": -1,
+					"member": -1,
+					"health_monitor": 3,
+					"l7policy": -1,
+					"l7rule": -1
+				}
+			}
+		`, loadBalancers)
+		th.Mux.HandleFunc("/octavia/v2/lbaas/quotas/"+tenantID, func(w http.ResponseWriter, r *http.Request) {
+			th.TestMethod(s.T(), r, "GET")
+			th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+
+			fmt.Fprint(w, body)
+		})
+	}
+}
+
+func registerExtensions(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/extensions/", func(w http.ResponseWriter, r *http.Request) {
 		th.TestMethod(s.T(), r, "GET")
 		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
 
+		alias := strings.TrimPrefix(r.URL.Path, "/v2.0/extensions/")
+		if s.UnavailableExtensions[alias] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if s.FailingExtensions[alias] {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
 		w.Header().Add("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 
 		fmt.Fprintf(w, `
 			{
-			  "ports": [
-			    {
-			      "admin_state_up": true,
-			      "allowed_address_pairs": [],
-			      "binding:host_id": "es-051",
-			      "binding:profile": {},
-			      "binding:vif_details": {
-				"ovs_hybrid_plug": true,
-				"port_filter": true
-			      },
-			      "binding:vif_type": "ovs",
-			      "binding:vnic_type": "normal",
-			      "created_at": "2016-10-20T13:07:02",
-			      "description": "",
-			      "device_id": "dd5dfb24-0e5d-41b1-a4a8-4693906d5f5b",
-			      "device_owner": "compute:nova",
-			      "extra_dhcp_opts": [],
-			      "fixed_ips": [
-				{
-				  "ip_address": "fdaf:f360:d434:0:f816:3eff:fe3b:fe08",
-				  "subnet_id": "ef512c07-9203-4121-9df6-e692a4bc84c5"
-				},
-				{
-				  "ip_address": "10.0.0.5",
-				  "subnet_id": "64c8fbe0-cb8a-41d7-9e65-56f33f9674cb"
-				}
-			      ],
-			      "id": "004e9c25-de09-4d4c-a2c3-50b05defaac9",
-			      "mac_address": "fa:16:3e:3b:fe:08",
-			      "name": "",
-			      "network_id": "28dd974d-0ec0-43cc-86ac-06773acb126f",
-			      "port_security_enabled": true,
-			      "revision": 10,
-			      "security_groups": [
-				"f47fd611-39d9-4999-9b10-41b19e03d40a"
-			      ],
-			      "status": "ACTIVE",
-			      "tenant_id": "222222",
-			      "updated_at": "2016-10-20T13:07:25"
-			    },
-			    {
-			      "admin_state_up": true,
-			      "allowed_address_pairs": [],
-			      "binding:host_id": "es-051",
-			      "binding:profile": {},
-			      "binding:vif_details": {
-				"ovs_hybrid_plug": true,
-				"port_filter": true
-			      },
-			      "binding:vif_type": "ovs",
-			      "binding:vnic_type": "normal",
-			      "created_at": "2016-10-20T13:07:01",
-			      "description": "",
-			      "device_id": "92fcd563-3605-4cfa-9241-520566d79e68",
-			      "device_owner": "compute:nova",
-			      "extra_dhcp_opts": [],
-			      "fixed_ips": [
-				{
-				  "ip_address": "fdaf:f360:d434:0:f816:3eff:fe17:68b3",
-				  "subnet_id": "ef512c07-9203-4121-9df6-e692a4bc84c5"
-				},
-				{
-				  "ip_address": "10.0.0.7",
-				  "subnet_id": "64c8fbe0-cb8a-41d7-9e65-56f33f9674cb"
-				}
-			      ],
-			      "id": "0a3bdc80-5b3e-4fca-baca-9716d94f56b5",
-			      "mac_address": "fa:16:3e:17:68:b3",
-			      "name": "",
-			      "network_id": "28dd974d-0ec0-43cc-86ac-06773acb126f",
-			      "port_security_enabled": true,
-			      "revision": 8,
-			      "security_groups": [
-				"f47fd611-39d9-4999-9b10-41b19e03d40a"
-			      ],
-			      "status": "ACTIVE",
-			      "tenant_id": "222222",
-			      "updated_at": "2016-10-20T13:07:23"
-			    },
-			    {
-			      "admin_state_up": true,
-			      "allowed_address_pairs": [],
-			      "binding:host_id": "es-051",
-			      "binding:profile": {},
-			      "binding:vif_details": {
-				"ovs_hybrid_plug": true,
-				"port_filter": true
-			      },
-			      "binding:vif_type": "ovs",
-			      "binding:vnic_type": "normal",
-			      "created_at": "2016-09-08T12:01:54",
-			      "description": "",
-			      "device_id": "a75c645a-6dcc-418c-9371-9be7054c395e",
-			      "device_owner": "network:router_interface",
-			      "extra_dhcp_opts": [],
-			      "fixed_ips": [
-				{
-				  "ip_address": "10.0.0.1",
-				  "subnet_id": "64c8fbe0-cb8a-41d7-9e65-56f33f9674cb"
+				"extension": {
+					"alias": "%s",
+					"name": "%s",
+					"description": "",
+					"updated": "2016-08-16T00:00:00-00:00",
+					"links": []
 				}
-			      ],
-			      "id": "11bc164c-c2dd-4809-9b04-0ef4aaefd8a2",
-			      "mac_address": "fa:16:3e:da:6a:9a",
-			      "name": "",
-			      "network_id": "28dd974d-0ec0-43cc-86ac-06773acb126f",
-			      "port_security_enabled": false,
-			      "revision": 8,
-			      "security_groups": [],
-			      "status": "ACTIVE",
-			      "tenant_id": "222222",
-			      "updated_at": "2016-10-20T13:07:23"
-			    }
-			  ]
 			}
-      `)
+		`, alias, alias)
 	})
 }
 
-func registerFloatingIPs(s *TestSuite) {
-	th.Mux.HandleFunc("/v2.0/floatingips", func(w http.ResponseWriter, r *http.Request) {
+func registerFWaaS(s *TestSuite) {
+	register := func(path, body string) {
+		th.Mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			th.TestMethod(s.T(), r, "GET")
+			th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+
+			fmt.Fprint(w, body)
+		})
+	}
+
+	register("/v2.0/fwaas/firewall_groups", `
+		{
+			"firewall_groups": [
+				{"id": "3b0ef8f4-82c7-44d4-a4fb-6177f9a21977", "tenant_id": "222222", "status": "ACTIVE", "ports": ["650bfd2f-7766-4a0d-839f-218f33e16998", "a3a8e8c1-9d1e-4f5b-8c6a-2b7d4e9f0a1b"]},
+				{"id": "a1b2c3d4-e5f6-4789-9abc-def012345678", "tenant_id": "222222", "status": "INACTIVE", "ports": []},
+				{"id": "0f1e2d3c-4b5a-4697-8877-665544332211", "tenant_id": "222222", "status": "PENDING_UPDATE", "ports": ["5c6d7e8f-9a0b-4c1d-8e2f-3a4b5c6d7e8f"]},
+				{"id": "9e8d7c6b-5a49-4837-a261-504f3e2d1c0b", "tenant_id": "111111", "status": "ERROR", "ports": []}
+			]
+		}
+	`)
+	register("/v2.0/fwaas/firewall_policies", `
+		{
+			"firewall_policies": [
+				{"id": "c69933c1-b472-44f9-8226-30dc4ffd454c", "tenant_id": "222222", "name": "policy1"},
+				{"id": "f4a3b2c1-d0e9-4f8a-b7c6-d5e4f3a2b1c0", "tenant_id": "222222", "name": "policy2"}
+			]
+		}
+	`)
+	register("/v2.0/fwaas/firewall_rules", `
+		{
+			"firewall_rules": [
+				{"id": "8722e0e0-9cc9-4490-9660-8c9a5732fbb0", "tenant_id": "222222", "protocol": "tcp", "action": "allow"},
+				{"id": "b1c2d3e4-f5a6-4b7c-8d9e-0f1a2b3c4d5e", "tenant_id": "222222", "protocol": "icmp", "action": "deny"},
+				{"id": "e5f6a7b8-c9d0-4e1f-a2b3-c4d5e6f7a8b9", "tenant_id": "111111", "protocol": "udp", "action": "allow"}
+			]
+		}
+	`)
+}
+
+func registerSecurityGroups(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/security-groups", func(w http.ResponseWriter, r *http.Request) {
 		th.TestMethod(s.T(), r, "GET")
 		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
 
@@ -1128,30 +3285,79 @@ func registerFloatingIPs(s *TestSuite) {
 		w.WriteHeader(http.StatusOK)
 
 		fmt.Fprintf(w, `
-		{
-			"floatingips": [
-				{
-					"floating_network_id": "28dc974d-0ec0-43cc-86ac-06773acb126f",
-					"router_id": null,
-					"fixed_ip_address": null,
-					"floating_ip_address": "192.0.0.4",
-					"tenant_id": "222222",
-					"status": "DOWN",
-					"port_id": "0a3bdc81-5b3e-4fca-baca-9716d94f56b5",
-					"id": "a75c645a-6dcd-418c-9371-9be7054c395e"
-				},
-				{
-					"floating_network_id": "ef51217-9203-4121-9df6-e692a4bc84c5",
-					"router_id": "0a24cb83-faf5-4d7f-b723-3144ed8a2167",
-					"fixed_ip_address": "192.0.0.2",
-					"floating_ip_address": "10.0.0.3",
-					"tenant_id": "222222",
-					"status": "DOWN",
-					"port_id": "004e9c25-de19-4d4c-a2c3-50b05defaac9",
-					"id": "bfdd31f8-ccda-4722-9319-13a7138e226c"
-				}
-			]
-		}
+			{
+				"security_groups": [
+					{
+						"description": "default",
+						"id": "85cc3048-abc3-43cc-89b3-377341426ac5",
+						"name": "default",
+						"security_group_rules": [],
+						"tenant_id": "222222"
+					},
+					{
+						"description": "web servers",
+						"id": "5c5e7e53-0a0e-4d5b-b2d6-4b6e3a8b3e21",
+						"name": "web",
+						"security_group_rules": [],
+						"tenant_id": "222222"
+					},
+					{
+						"description": "default",
+						"id": "9d1c8d7c-3f5c-4f6b-8a3e-0c1d4f0a6b92",
+						"name": "default",
+						"security_group_rules": [],
+						"tenant_id": "111111"
+					}
+				]
+			}
+		`)
+	})
+}
+
+func registerSecurityGroupRules(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/security-group-rules", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"security_group_rules": [
+					{
+						"direction": "egress",
+						"ethertype": "IPv6",
+						"id": "3c0e45ff-adaf-4124-b083-bf390e5482ff",
+						"security_group_id": "85cc3048-abc3-43cc-89b3-377341426ac5",
+						"tenant_id": "222222"
+					},
+					{
+						"direction": "egress",
+						"ethertype": "IPv4",
+						"id": "93aa42e5-80db-4581-9391-3a608bd0e448",
+						"security_group_id": "85cc3048-abc3-43cc-89b3-377341426ac5",
+						"tenant_id": "222222"
+					},
+					{
+						"direction": "ingress",
+						"ethertype": "IPv4",
+						"id": "c0b09f00-1d49-4e64-a0a7-8a186d928138",
+						"port_range_max": 80,
+						"port_range_min": 80,
+						"protocol": "tcp",
+						"security_group_id": "5c5e7e53-0a0e-4d5b-b2d6-4b6e3a8b3e21",
+						"tenant_id": "222222"
+					},
+					{
+						"direction": "egress",
+						"ethertype": "IPv4",
+						"id": "f7d45c89-008e-4bab-88ad-d6811724c51c",
+						"security_group_id": "9d1c8d7c-3f5c-4f6b-8a3e-0c1d4f0a6b92",
+						"tenant_id": "111111"
+					}
+				]
+			}
 		`)
 	})
 }
@@ -1205,6 +3411,59 @@ func registerQuotas(s *TestSuite) {
 	})
 }
 
+func registerQuotaDetails(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/quotas/222222/details", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"quota": {
+					"subnet": {"limit": 10, "used": 3, "reserved": 0},
+					"network": {"limit": 13, "used": 2, "reserved": 0},
+					"floatingip": {"limit": 50, "used": 2, "reserved": 0},
+					"subnetpool": {"limit": -1, "used": 0, "reserved": 0},
+					"security_group_rule": {"limit": 100, "used": 3, "reserved": 0},
+					"security_group": {"limit": 10, "used": 2, "reserved": 0},
+					"router": {"limit": 15, "used": 4, "reserved": 0},
+					"rbac_policy": {"limit": -1, "used": 0, "reserved": 0},
+					"port": {"limit": 50, "used": 3, "reserved": 1},
+					"loadbalancer": {"limit": 10, "used": 1, "reserved": 0},
+					"member": {"limit": -1, "used": 2, "reserved": 0},
+					"ipsec_site_connection": {"limit": 10, "used": 3, "reserved": 0},
+					"firewall_group": {"limit": 10, "used": 3, "reserved": 0}
+				}
+			}
+		`)
+	})
+	th.Mux.HandleFunc("/v2.0/quotas/111111/details", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"quota": {
+					"subnet": {"limit": 11, "used": 0, "reserved": 0},
+					"network": {"limit": 12, "used": 1, "reserved": 0},
+					"floatingip": {"limit": 51, "used": 0, "reserved": 0},
+					"subnetpool": {"limit": 0, "used": 0, "reserved": 0},
+					"security_group_rule": {"limit": 101, "used": 1, "reserved": 0},
+					"security_group": {"limit": 11, "used": 1, "reserved": 0},
+					"router": {"limit": 16, "used": 0, "reserved": 0},
+					"rbac_policy": {"limit": 0, "used": 0, "reserved": 0},
+					"port": {"limit": 51, "used": 0, "reserved": 1}
+				}
+			}
+		`)
+	})
+}
+
 func registerEndpoints(s *TestSuite) {
 	th.Mux.HandleFunc("/v2/endpoints", func(w http.ResponseWriter, r *http.Request) {
 		th.TestMethod(s.T(), r, "GET")
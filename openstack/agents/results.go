@@ -0,0 +1,77 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agents
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/rackspace/gophercloud"
+)
+
+//heartbeatLayouts formats of heartbeat timestamps reported by Neutron (in UTC)
+var heartbeatLayouts = []string{
+	"2006-01-02 15:04:05.999999",
+	"2006-01-02T15:04:05.999999",
+	time.RFC3339,
+}
+
+//Agent represents state of a single Neutron agent
+type Agent struct {
+	ID                 string `mapstructure:"id"`
+	AgentType          string `mapstructure:"agent_type"`
+	Binary             string `mapstructure:"binary"`
+	Host               string `mapstructure:"host"`
+	AvailabilityZone   string `mapstructure:"availability_zone"`
+	Alive              bool   `mapstructure:"alive"`
+	AdminStateUp       bool   `mapstructure:"admin_state_up"`
+	HeartbeatTimestamp string `mapstructure:"heartbeat_timestamp"`
+}
+
+// Heartbeat returns time of the last heartbeat of agent.
+func (a Agent) Heartbeat() (time.Time, error) {
+	for _, layout := range heartbeatLayouts {
+		if t, err := time.Parse(layout, a.HeartbeatTimestamp); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("Unknown format of heartbeat timestamp %q", a.HeartbeatTimestamp)
+}
+
+//ListResult represents the result of a list operation.
+type ListResult struct {
+	gophercloud.Result
+}
+
+// Extract will get the agents out of the ListResult object.
+func (r ListResult) Extract() ([]Agent, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	body, ok := r.Body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Expected an object, but was %#v", r.Body)
+	}
+
+	var agents []Agent
+	err := mapstructure.Decode(body["agents"], &agents)
+	return agents, err
+}
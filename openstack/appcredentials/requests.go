@@ -0,0 +1,94 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package appcredentials
+
+import (
+	"net/http"
+
+	"github.com/rackspace/gophercloud"
+)
+
+const (
+	tokensPath = "auth/tokens"
+)
+
+// AuthOptions contains application credential used to obtain a token from Identity API v3.
+// Credential is identified by ID or by Name, in the latter case owner of the credential (UserID or Username with domain) is required.
+type AuthOptions struct {
+	ID         string
+	Name       string
+	Secret     string
+	UserID     string
+	Username   string
+	DomainID   string
+	DomainName string
+}
+
+// Create will request a new token using application credential. To extract the token ID and service catalog
+// from the result, call the ExtractTokenID and ExtractServiceCatalog methods on the CreateResult.
+func Create(client *gophercloud.ServiceClient, opts AuthOptions) CreateResult {
+	var res CreateResult
+
+	credential := map[string]interface{}{
+		"secret": opts.Secret,
+	}
+	if opts.ID != "" {
+		credential["id"] = opts.ID
+	} else {
+		credential["name"] = opts.Name
+		credential["user"] = userReq(opts)
+	}
+
+	reqBody := map[string]interface{}{
+		"auth": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"methods":                []string{"application_credential"},
+				"application_credential": credential,
+			},
+		},
+	}
+
+	reqOpts := gophercloud.RequestOpts{
+		OkCodes: []int{http.StatusCreated},
+	}
+	url := client.ServiceURL(tokensPath)
+	resp, err := client.Post(url, reqBody, &res.Body, &reqOpts)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	res.Header = resp.Header
+	return res
+}
+
+// userReq builds owner of application credential identified by name
+func userReq(opts AuthOptions) map[string]interface{} {
+	if opts.UserID != "" {
+		return map[string]interface{}{"id": opts.UserID}
+	}
+
+	user := map[string]interface{}{"name": opts.Username}
+	if opts.DomainID != "" {
+		user["domain"] = map[string]interface{}{"id": opts.DomainID}
+	} else {
+		user["domain"] = map[string]interface{}{"name": opts.DomainName}
+	}
+	return user
+}
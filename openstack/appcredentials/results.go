@@ -0,0 +1,68 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package appcredentials
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/rackspace/gophercloud"
+	tokens3 "github.com/rackspace/gophercloud/openstack/identity/v3/tokens"
+)
+
+const (
+	subjectTokenHeader = "X-Subject-Token"
+)
+
+//CreateResult represents the result of a token creation.
+type CreateResult struct {
+	gophercloud.Result
+}
+
+// ExtractTokenID will get the ID of created token out of the CreateResult object.
+func (r CreateResult) ExtractTokenID() (string, error) {
+	if r.Err != nil {
+		return "", r.Err
+	}
+
+	tokenID := r.Header.Get(subjectTokenHeader)
+	if tokenID == "" {
+		return "", fmt.Errorf("Response does not contain %s header", subjectTokenHeader)
+	}
+	return tokenID, nil
+}
+
+// ExtractServiceCatalog will get the service catalog out of the CreateResult object.
+func (r CreateResult) ExtractServiceCatalog() (*tokens3.ServiceCatalog, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	var resp struct {
+		Token struct {
+			Entries []tokens3.CatalogEntry `mapstructure:"catalog"`
+		} `mapstructure:"token"`
+	}
+	err := mapstructure.Decode(r.Body, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &tokens3.ServiceCatalog{Entries: resp.Token.Entries}, nil
+}
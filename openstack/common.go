@@ -20,9 +20,20 @@ limitations under the License.
 package openstack
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/openstack/appcredentials"
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/redact"
 	"github.com/intelsdi-x/snap/core/serror"
 	"github.com/rackspace/gophercloud"
 	"github.com/rackspace/gophercloud/openstack"
+	"github.com/rackspace/gophercloud/openstack/utils"
+)
+
+const (
+	identityV2 = "v2.0"
+	identityV3 = "v3.0"
 )
 
 // Authenticate is used to authenticate user for given tenant. Request is send to provided endpoint
@@ -50,7 +61,118 @@ func Authenticate(endpoint, user, password, tenant, domain_name, domain_id strin
 			"Password":         password,
 			"TenantName":       tenant,
 			"AllowReauth":      true}
-		return nil, serror.New(err, f)
+		return nil, redact.New(err, f)
 	}
 	return provider, nil
 }
+
+// AuthenticateWithApplicationCredential is used to authenticate with Keystone application credential identified by ID or by name
+// of the credential and its owner. Request is send to provided endpoint, application credentials require Identity API v3.
+// Returns authenticated provider client, which is used as a base for service clients.
+func AuthenticateWithApplicationCredential(endpoint, credentialID, credentialName, secret, user, domain_name, domain_id string) (*gophercloud.ProviderClient, serror.SnapError) {
+	f := map[string]interface{}{
+		"IdentityEndpoint":          endpoint,
+		"ApplicationCredentialID":   credentialID,
+		"ApplicationCredentialName": credentialName,
+		"Username":                  user,
+		"AllowReauth":               true}
+
+	provider, err := openstack.NewClient(endpoint)
+	if err != nil {
+		return nil, redact.New(err, f)
+	}
+
+	if provider.IdentityEndpoint == "" {
+		provider.IdentityEndpoint = provider.IdentityBase + "v3/"
+	}
+	if !strings.HasSuffix(provider.IdentityEndpoint, "/v3/") {
+		return nil, redact.New(fmt.Errorf("Application credentials require Identity API v3"), f)
+	}
+
+	authOpts := appcredentials.AuthOptions{
+		ID:       credentialID,
+		Name:     credentialName,
+		Secret:   secret,
+		Username: user,
+	}
+	if domain_name != "" && domain_id == "" {
+		authOpts.DomainName = domain_name
+	}
+	if domain_id != "" && domain_name == "" {
+		authOpts.DomainID = domain_id
+	}
+
+	err = applicationCredentialAuth(provider, authOpts)
+	if err != nil {
+		return nil, redact.New(err, f)
+	}
+	return provider, nil
+}
+
+// applicationCredentialAuth obtains token for provider client and sets up reauthentication
+func applicationCredentialAuth(provider *gophercloud.ProviderClient, authOpts appcredentials.AuthOptions) error {
+	result := appcredentials.Create(openstack.NewIdentityV3(provider), authOpts)
+
+	tokenID, err := result.ExtractTokenID()
+	if err != nil {
+		return err
+	}
+
+	catalog, err := result.ExtractServiceCatalog()
+	if err != nil {
+		return err
+	}
+
+	provider.TokenID = tokenID
+	provider.ReauthFunc = func() error {
+		provider.TokenID = ""
+		return applicationCredentialAuth(provider, authOpts)
+	}
+	provider.EndpointLocator = func(opts gophercloud.EndpointOpts) (string, error) {
+		return openstack.V3EndpointURL(catalog, opts)
+	}
+	return nil
+}
+
+// NewIdentityClient creates service client for Identity API in the same version which is chosen for authentication,
+// version is taken from auth URL or, if auth URL does not contain it, from versions advertised by Identity endpoint
+func NewIdentityClient(provider *gophercloud.ProviderClient) (*gophercloud.ServiceClient, serror.SnapError) {
+	versions := []*utils.Version{
+		{ID: identityV2, Priority: 20, Suffix: "/v2.0/"},
+		{ID: identityV3, Priority: 30, Suffix: "/v3/"},
+	}
+
+	chosen, endpoint, err := utils.ChooseVersion(provider, versions)
+	if err != nil {
+		f := map[string]interface{}{"IdentityEndpoint": provider.IdentityEndpoint}
+		return nil, redact.New(err, f)
+	}
+
+	var client *gophercloud.ServiceClient
+	if chosen.ID == identityV3 {
+		client = openstack.NewIdentityV3(provider)
+	} else {
+		client = openstack.NewIdentityV2(provider)
+	}
+	client.Endpoint = endpoint
+	return client, nil
+}
+
+// NewOctaviaClient creates service client for LBaaS v2 API of Octavia, it returns nil if Octavia endpoint (service type load-balancer)
+// is not present in service catalog, load balancing resources are then retrieved from Neutron LBaaS v2 extension by network client
+func NewOctaviaClient(provider *gophercloud.ProviderClient) *gophercloud.ServiceClient {
+	url, err := provider.EndpointLocator(gophercloud.EndpointOpts{Type: "load-balancer", Availability: gophercloud.AvailabilityPublic})
+	if err != nil {
+		return nil
+	}
+	return &gophercloud.ServiceClient{
+		ProviderClient: provider,
+		Endpoint:       url,
+		ResourceBase:   url + "v2/",
+	}
+}
+
+// isIdentityV3 checks whether service client is used to access Identity API v3
+func isIdentityV3(client *gophercloud.ServiceClient) bool {
+	return strings.HasSuffix(gophercloud.NormalizeURL(client.Endpoint), "/v3/")
+}
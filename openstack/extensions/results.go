@@ -0,0 +1,55 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extensions
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/rackspace/gophercloud"
+)
+
+//Extension represents Neutron extension advertised by extension discovery
+type Extension struct {
+	Alias       string `mapstructure:"alias"`
+	Name        string `mapstructure:"name"`
+	Description string `mapstructure:"description"`
+	Updated     string `mapstructure:"updated"`
+}
+
+//GetResult represents the result of a get operation.
+type GetResult struct {
+	gophercloud.Result
+}
+
+// Extract will get the extension out of the GetResult object.
+func (r GetResult) Extract() (Extension, error) {
+	var extension Extension
+	if r.Err != nil {
+		return extension, r.Err
+	}
+	body, ok := r.Body.(map[string]interface{})
+	if !ok {
+		return extension, fmt.Errorf("Expected an object, but was %#v", r.Body)
+	}
+
+	err := mapstructure.Decode(body["extension"], &extension)
+	return extension, err
+}
@@ -0,0 +1,42 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipavailability
+
+import (
+	"net/http"
+
+	"github.com/rackspace/gophercloud"
+)
+
+const (
+	ipAvailabilitiesPath = "network-ip-availabilities"
+)
+
+// List will retrieve numbers of allocatable and used IP addresses of all networks and their subnets.
+// It requires network IP availability extension, to extract the availabilities from the result, call the Extract method on the ListResult.
+func List(client *gophercloud.ServiceClient) ListResult {
+	var res ListResult
+	reqOpts := gophercloud.RequestOpts{
+		OkCodes: []int{http.StatusOK},
+	}
+	url := client.ServiceURL(ipAvailabilitiesPath)
+	_, res.Err = client.Get(url, &res.Body, &reqOpts)
+	return res
+}
@@ -0,0 +1,68 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipavailability
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/rackspace/gophercloud"
+)
+
+//SubnetIPAvailability represents number of allocatable and used IP addresses of a single subnet
+//Numbers are floating point, because IPv6 subnets may contain more addresses than int64 can hold
+type SubnetIPAvailability struct {
+	SubnetID   string  `mapstructure:"subnet_id"`
+	SubnetName string  `mapstructure:"subnet_name"`
+	CIDR       string  `mapstructure:"cidr"`
+	IPVersion  int     `mapstructure:"ip_version"`
+	TotalIPs   float64 `mapstructure:"total_ips"`
+	UsedIPs    float64 `mapstructure:"used_ips"`
+}
+
+//NetworkIPAvailability represents number of allocatable and used IP addresses of a network and its subnets
+type NetworkIPAvailability struct {
+	NetworkID   string                 `mapstructure:"network_id"`
+	NetworkName string                 `mapstructure:"network_name"`
+	TenantID    string                 `mapstructure:"tenant_id"`
+	TotalIPs    float64                `mapstructure:"total_ips"`
+	UsedIPs     float64                `mapstructure:"used_ips"`
+	Subnets     []SubnetIPAvailability `mapstructure:"subnet_ip_availability"`
+}
+
+//ListResult represents the result of a list operation.
+type ListResult struct {
+	gophercloud.Result
+}
+
+// Extract will get the IP availabilities of networks out of the ListResult object.
+func (r ListResult) Extract() ([]NetworkIPAvailability, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	body, ok := r.Body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Expected an object, but was %#v", r.Body)
+	}
+
+	var availabilities []NetworkIPAvailability
+	err := mapstructure.Decode(body["network_ip_availabilities"], &availabilities)
+	return availabilities, err
+}
@@ -0,0 +1,84 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancers
+
+import (
+	"net/http"
+
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/openstack/tenantresources"
+	"github.com/rackspace/gophercloud"
+	"github.com/rackspace/gophercloud/pagination"
+)
+
+// Collections of LBaaS v2 API, the same paths are served by Neutron LBaaS v2 extension and by Octavia
+var (
+	// LoadBalancers collection of load balancers
+	LoadBalancers = tenantresources.Resource{Path: "lbaas/loadbalancers", Key: "loadbalancers"}
+	// Listeners collection of listeners
+	Listeners = tenantresources.Resource{Path: "lbaas/listeners", Key: "listeners"}
+	// Pools collection of pools
+	Pools = tenantresources.Resource{Path: "lbaas/pools", Key: "pools"}
+	// HealthMonitors collection of health monitors
+	HealthMonitors = tenantresources.Resource{Path: "lbaas/healthmonitors", Key: "healthmonitors"}
+)
+
+// MembersKey is a name of collection of pool members in response body
+const MembersKey = "members"
+
+// Members returns collection of members of pool with given ID, members are not listed across pools
+func Members(poolID string) tenantresources.Resource {
+	return tenantresources.Resource{Path: "lbaas/pools/" + poolID + "/members", Key: MembersKey}
+}
+
+// StatusFields limits returned attributes of load balancing resources to those needed to count them per tenant and status,
+// Octavia reports owner as project_id
+var StatusFields = []string{"id", "tenant_id", "project_id", "provisioning_status", "operating_status"}
+
+// PoolFields limits returned attributes of pools to status fields and references to members
+var PoolFields = append(append([]string{}, StatusFields...), "members")
+
+// List enumerates load balancing resources of given collection, pages are retrieved using links returned by API.
+func List(client *gophercloud.ServiceClient, resource tenantresources.Resource, opts *tenantresources.ListOpts) pagination.Pager {
+	createPage := func(r pagination.PageResult) pagination.Page {
+		return ResourcePage{LinkedPageBase: pagination.LinkedPageBase{PageResult: r}, key: resource.Key}
+	}
+
+	url := client.ServiceURL(resource.Path)
+	if opts != nil {
+		q, err := gophercloud.BuildQueryString(opts)
+		if err != nil {
+			return pagination.Pager{Err: err}
+		}
+		url += q.String()
+	}
+	return pagination.NewPager(client, url, createPage)
+}
+
+// GetQuotas retrieves quotas of load balancing resources of given project from Octavia,
+// to extract them from the result, call the Extract method on the QuotasResult.
+func GetQuotas(client *gophercloud.ServiceClient, projectID string) QuotasResult {
+	var res QuotasResult
+	reqOpts := gophercloud.RequestOpts{
+		OkCodes: []int{http.StatusOK},
+	}
+	url := client.ServiceURL("lbaas", "quotas", projectID)
+	_, res.Err = client.Get(url, &res.Body, &reqOpts)
+	return res
+}
@@ -0,0 +1,144 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancers
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/rackspace/gophercloud"
+	"github.com/rackspace/gophercloud/pagination"
+)
+
+// LBResource represents attributes of load balancing resource which indicate its owner and status
+type LBResource struct {
+	ID                 string `mapstructure:"id"`
+	TenantID           string `mapstructure:"tenant_id"`
+	ProjectID          string `mapstructure:"project_id"`
+	ProvisioningStatus string `mapstructure:"provisioning_status"`
+	OperatingStatus    string `mapstructure:"operating_status"`
+}
+
+// Owner returns ID of tenant which owns resource, tenant ID is preferred over project ID
+func (r LBResource) Owner() string {
+	if r.TenantID != "" {
+		return r.TenantID
+	}
+	return r.ProjectID
+}
+
+// Pool represents attributes of pool and references to its members
+type Pool struct {
+	LBResource `mapstructure:",squash"`
+	Members    []MemberRef `mapstructure:"members"`
+}
+
+// MemberRef represents reference to member of pool
+type MemberRef struct {
+	ID string `mapstructure:"id"`
+}
+
+// ResourcePage is a single page of load balancing resources of one collection.
+type ResourcePage struct {
+	pagination.LinkedPageBase
+	key string
+}
+
+// IsEmpty determines whether or not a page contains any resources.
+func (page ResourcePage) IsEmpty() (bool, error) {
+	resources, err := ExtractLBResources(page)
+	if err != nil {
+		return false, err
+	}
+	return len(resources) == 0, nil
+}
+
+// NextPageURL extracts the "next" link from the <collection>_links section of the result.
+func (page ResourcePage) NextPageURL() (string, error) {
+	body, ok := page.Body.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("Expected an object, but was %#v", page.Body)
+	}
+
+	var links []gophercloud.Link
+	if err := mapstructure.Decode(body[page.key+"_links"], &links); err != nil {
+		return "", err
+	}
+	return gophercloud.ExtractNextURL(links)
+}
+
+// ExtractLBResources returns a slice of load balancing resources contained in a single page of results.
+func ExtractLBResources(page pagination.Page) ([]LBResource, error) {
+	var resources []LBResource
+	err := decodePage(page, &resources)
+	return resources, err
+}
+
+// ExtractPools returns a slice of pools contained in a single page of results.
+func ExtractPools(page pagination.Page) ([]Pool, error) {
+	var pools []Pool
+	err := decodePage(page, &pools)
+	return pools, err
+}
+
+// decodePage decodes resources contained in a single page of results into given slice.
+func decodePage(page pagination.Page, resources interface{}) error {
+	resourcePage := page.(ResourcePage)
+	body, ok := resourcePage.Body.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("Expected an object, but was %#v", resourcePage.Body)
+	}
+	return mapstructure.Decode(body[resourcePage.key], resources)
+}
+
+// octaviaQuotaNames maps names of Octavia quotas to names of the same quotas in Neutron LBaaS v2
+var octaviaQuotaNames = map[string]string{
+	"load_balancer":  "loadbalancer",
+	"health_monitor": "healthmonitor",
+}
+
+// QuotasResult represents the result of a get quotas operation.
+type QuotasResult struct {
+	gophercloud.Result
+}
+
+// Extract returns quotas of project keyed by names used by Neutron LBaaS v2 (loadbalancer, listener, pool, member, healthmonitor).
+func (r QuotasResult) Extract() (map[string]int64, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	var resp map[string]map[string]int64
+	if err := mapstructure.Decode(r.Body, &resp); err != nil {
+		return nil, err
+	}
+	quota, ok := resp["quota"]
+	if !ok {
+		return nil, fmt.Errorf("Expected quota object, but was %#v", r.Body)
+	}
+
+	quotas := map[string]int64{}
+	for name, limit := range quota {
+		if neutronName, ok := octaviaQuotaNames[name]; ok {
+			name = neutronName
+		}
+		quotas[name] = limit
+	}
+	return quotas, nil
+}
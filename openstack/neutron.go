@@ -21,36 +21,62 @@ package openstack
 
 import (
 	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
 
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/openstack/agents"
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/openstack/extensions"
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/openstack/ipavailability"
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/openstack/loadbalancers"
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/openstack/projects"
 	"github.com/intelsdi-x/snap-plugin-collector-neutron/openstack/tenantquotas"
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/openstack/tenantresources"
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/redact"
 	"github.com/intelsdi-x/snap-plugin-collector-neutron/types"
 	"github.com/intelsdi-x/snap/core/serror"
 	"github.com/rackspace/gophercloud"
 	"github.com/rackspace/gophercloud/openstack/identity/v2/tenants"
-	"github.com/rackspace/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
-	"github.com/rackspace/gophercloud/openstack/networking/v2/extensions/layer3/routers"
-	"github.com/rackspace/gophercloud/openstack/networking/v2/networks"
-	"github.com/rackspace/gophercloud/openstack/networking/v2/ports"
-	"github.com/rackspace/gophercloud/openstack/networking/v2/subnets"
+	"github.com/rackspace/gophercloud/pagination"
 )
 
 const (
 	quotaPath = "quota"
+
+	//FWaaSExtensionAlias alias of FWaaS v2 extension
+	FWaaSExtensionAlias = "fwaas_v2"
+
+	//VPNaaSExtensionAlias alias of VPNaaS extension
+	VPNaaSExtensionAlias = "vpnaas"
+
+	//QoSExtensionAlias alias of QoS extension
+	QoSExtensionAlias = "qos"
+
+	//LBaaSExtensionAlias alias of Neutron LBaaS v2 extension
+	LBaaSExtensionAlias = "lbaasv2"
 )
 
 // GetAllTenants is used to retrieve list of available tenants
+// Tenants are listed by Identity API v2 or, in case of v3 client, projects are listed instead
 func GetAllTenants(client *gophercloud.ServiceClient) ([]types.Tenant, serror.SnapError) {
+	if isIdentityV3(client) {
+		return getAllProjects(client)
+	}
+
 	tnts := []types.Tenant{}
 
 	pager := tenants.List(client, nil)
 	page, err := pager.AllPages()
 	if err != nil {
-		return tnts, serror.New(err)
+		return tnts, redact.New(err)
 	}
 
 	tenantList, err := tenants.ExtractTenants(page)
 	if err != nil {
-		return tnts, serror.New(err)
+		return tnts, redact.New(err)
 	}
 
 	for _, t := range tenantList {
@@ -59,179 +85,1261 @@ func GetAllTenants(client *gophercloud.ServiceClient) ([]types.Tenant, serror.Sn
 	return tnts, nil
 }
 
-// GetNetworkCountPerTenant is used to retrieve number of networks per tenant
-func GetNetworkCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant) (map[string]int64, serror.SnapError) {
-	tenantNetworksCount := map[string]int64{}
+// getAllProjects is used to retrieve list of available projects using Identity API v3
+func getAllProjects(client *gophercloud.ServiceClient) ([]types.Tenant, serror.SnapError) {
+	tnts := []types.Tenant{}
 
-	pager := networks.List(client, nil)
+	pager := projects.List(client, nil)
 	page, err := pager.AllPages()
 	if err != nil {
-		return tenantNetworksCount, serror.New(err)
+		return tnts, redact.New(err)
 	}
 
-	networkList, err := networks.ExtractNetworks(page)
+	projectList, err := projects.ExtractProjects(page)
 	if err != nil {
-		return tenantNetworksCount, serror.New(err)
+		return tnts, redact.New(err)
 	}
 
-	for _, tnt := range tenantList {
-		if _, ok := tenantNetworksCount[tnt.Name]; !ok {
-			tenantNetworksCount[tnt.Name] = 0
+	for _, p := range projectList {
+		if p.IsDomain {
+			continue
+		}
+		tnts = append(tnts, types.Tenant{Name: p.Name, ID: p.ID, DomainID: p.DomainID, ParentID: p.ParentID})
+	}
+	return tnts, nil
+}
+
+//NetworkCounts holds numbers of networks keyed by tenant ID and attributes of networks keyed by network ID
+type NetworkCounts struct {
+	Total    map[string]int64
+	Networks map[string]tenantresources.Network
+	//QoSPolicy holds numbers of networks with QoS policy attached keyed by tenant ID
+	QoSPolicy map[string]int64
+	Unlisted  UnlistedResources
+}
+
+//GetNetworkDetailsPerTenant is used to retrieve number of networks per tenant together with attributes of networks (name, shared and external flags)
+func GetNetworkDetailsPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (NetworkCounts, serror.SnapError) {
+	counts := NetworkCounts{Total: initTenantCounts(tenantList), Networks: map[string]tenantresources.Network{}, QoSPolicy: initTenantCounts(tenantList)}
+	unlisted := newUnlistedTracker(tenantList)
+
+	err := tenantresources.List(client, tenantresources.Networks, withFields(opts, tenantresources.NetworkFields)).EachPage(func(page pagination.Page) (bool, error) {
+		networks, err := tenantresources.ExtractNetworks(page)
+		if err != nil {
+			return false, err
 		}
 
-		for _, net := range networkList {
-			if tnt.ID == net.TenantID {
-				tenantNetworksCount[tnt.Name]++
+		for _, network := range networks {
+			counts.Total[network.TenantID]++
+			unlisted.add("Total", "", network.ID, network.TenantID)
+			counts.Networks[network.ID] = network
+			if network.QoSPolicyID != "" {
+				counts.QoSPolicy[network.TenantID]++
+				unlisted.add("QoSPolicy", "", network.ID, network.TenantID)
 			}
 		}
+		return true, nil
+	})
+	if err != nil {
+		return NetworkCounts{}, redact.New(err, map[string]interface{}{"resource": tenantresources.Networks.Key})
 	}
-	return tenantNetworksCount, nil
+	counts.Unlisted = unlisted.resources
+	return counts, nil
 }
 
-// GetSubnetsCountPerTenant is used to retrieve number of subnets per tenant
+//GetNetworkCountPerTenant is used to retrieve number of networks per tenant, counts are keyed by tenant ID
+func GetNetworkCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant) (map[string]int64, serror.SnapError) {
+	counts, err := GetNetworkDetailsPerTenant(client, tenantList, nil)
+	if err != nil {
+		return map[string]int64{}, err
+	}
+	return listedTenantCounts(counts.Total, tenantList), nil
+}
+
+//SubnetCounts holds numbers of subnets keyed by tenant ID and by network ID and attributes of subnets keyed by subnet ID
+type SubnetCounts struct {
+	Total      map[string]int64
+	PerNetwork map[string]int64
+	Subnets    map[string]tenantresources.Subnet
+	//PerSubnetPool holds numbers of addresses of subnets allocated from subnet pools keyed by pool ID
+	PerSubnetPool map[string]float64
+	Unlisted      UnlistedResources
+}
+
+//GetSubnetDetailsPerTenant is used to retrieve number of subnets per tenant and per network together with address space allocated from subnet pools
+func GetSubnetDetailsPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (SubnetCounts, serror.SnapError) {
+	counts := SubnetCounts{Total: initTenantCounts(tenantList), PerNetwork: map[string]int64{}, Subnets: map[string]tenantresources.Subnet{}, PerSubnetPool: map[string]float64{}}
+	poolAddresses := map[string]*big.Int{}
+	unlisted := newUnlistedTracker(tenantList)
+
+	err := tenantresources.List(client, tenantresources.Subnets, withFields(opts, tenantresources.SubnetFields)).EachPage(func(page pagination.Page) (bool, error) {
+		subnets, err := tenantresources.ExtractSubnets(page)
+		if err != nil {
+			return false, err
+		}
+
+		for _, subnet := range subnets {
+			counts.Total[subnet.TenantID]++
+			unlisted.add("Total", "", subnet.ID, subnet.TenantID)
+			counts.PerNetwork[subnet.NetworkID]++
+			counts.Subnets[subnet.ID] = subnet
+			if subnet.SubnetPoolID == "" {
+				continue
+			}
+			if poolAddresses[subnet.SubnetPoolID] == nil {
+				poolAddresses[subnet.SubnetPoolID] = new(big.Int)
+			}
+			poolAddresses[subnet.SubnetPoolID].Add(poolAddresses[subnet.SubnetPoolID], getCIDRSize(subnet.CIDR))
+		}
+		return true, nil
+	})
+	if err != nil {
+		return SubnetCounts{}, redact.New(err, map[string]interface{}{"resource": tenantresources.Subnets.Key})
+	}
+	for id, size := range poolAddresses {
+		counts.PerSubnetPool[id], _ = new(big.Float).SetInt(size).Float64()
+	}
+	counts.Unlisted = unlisted.resources
+	return counts, nil
+}
+
+//GetSubnetsCountPerTenant is used to retrieve number of subnets per tenant, counts are keyed by tenant ID
 func GetSubnetsCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant) (map[string]int64, serror.SnapError) {
-	tenantSubnetsCount := map[string]int64{}
+	counts, err := GetSubnetDetailsPerTenant(client, tenantList, nil)
+	if err != nil {
+		return map[string]int64{}, err
+	}
+	return listedTenantCounts(counts.Total, tenantList), nil
+}
 
-	pager := subnets.List(client, nil)
-	page, err := pager.AllPages()
+//RouterCounts holds numbers of routers keyed by tenant ID and attributes of routers keyed by router ID
+type RouterCounts struct {
+	Total    map[string]int64
+	Routers  map[string]tenantresources.Router
+	Unlisted UnlistedResources
+}
+
+//GetRouterDetailsPerTenant is used to retrieve number of routers per tenant together with attributes of routers (name, external gateway)
+func GetRouterDetailsPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (RouterCounts, serror.SnapError) {
+	counts := RouterCounts{Total: initTenantCounts(tenantList), Routers: map[string]tenantresources.Router{}}
+	unlisted := newUnlistedTracker(tenantList)
+
+	err := tenantresources.List(client, tenantresources.Routers, withFields(opts, tenantresources.RouterFields)).EachPage(func(page pagination.Page) (bool, error) {
+		routers, err := tenantresources.ExtractRouters(page)
+		if err != nil {
+			return false, err
+		}
+
+		for _, router := range routers {
+			counts.Total[router.TenantID]++
+			unlisted.add("Total", "", router.ID, router.TenantID)
+			counts.Routers[router.ID] = router
+		}
+		return true, nil
+	})
 	if err != nil {
-		return tenantSubnetsCount, serror.New(err)
+		return RouterCounts{}, redact.New(err, map[string]interface{}{"resource": tenantresources.Routers.Key})
 	}
+	counts.Unlisted = unlisted.resources
+	return counts, nil
+}
 
-	subnetList, err := subnets.ExtractSubnets(page)
+//GetRoutersCountPerTenant is used to retrieve number of routers per tenant, counts are keyed by tenant ID
+func GetRoutersCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant) (map[string]int64, serror.SnapError) {
+	counts, err := GetRouterDetailsPerTenant(client, tenantList, nil)
 	if err != nil {
-		return tenantSubnetsCount, serror.New(err)
+		return map[string]int64{}, err
+	}
+	return listedTenantCounts(counts.Total, tenantList), nil
+}
+
+//PortStatuses statuses of ports which are counted for every known tenant, even if tenant has no such ports
+var PortStatuses = []string{"ACTIVE", "DOWN", "BUILD", "ERROR"}
+
+//classes of port device owners
+const (
+	PortOwnerCompute         = "compute"
+	PortOwnerRouterInterface = "router_interface"
+	PortOwnerDHCP            = "dhcp"
+	PortOwnerFloatingIP      = "floatingip"
+	PortOwnerUnbound         = "unbound"
+	PortOwnerOther           = "other"
+)
+
+//PortOwnerClasses classes of port device owners which are counted for every known tenant
+var PortOwnerClasses = []string{PortOwnerCompute, PortOwnerRouterInterface, PortOwnerDHCP, PortOwnerFloatingIP, PortOwnerUnbound, PortOwnerOther}
+
+//PortCounts holds numbers of ports keyed by tenant ID
+type PortCounts struct {
+	Total map[string]int64
+	//Status holds numbers of ports keyed by status and tenant ID
+	Status map[string]map[string]int64
+	//Owner holds numbers of ports keyed by class of device owner and tenant ID
+	Owner map[string]map[string]int64
+	//PerNetwork holds numbers of ports keyed by network ID
+	PerNetwork map[string]int64
+	//PerSubnet holds numbers of fixed IPs of ports keyed by subnet ID
+	PerSubnet map[string]int64
+	//RouterInterfaces holds numbers of router interface ports keyed by router ID
+	RouterInterfaces map[string]int64
+	//QoSPolicy holds numbers of ports with QoS policy attached keyed by tenant ID
+	QoSPolicy map[string]int64
+	Unlisted  UnlistedResources
+}
+
+//routerInterfaceOwners device owners of ports which are interfaces of routers (legacy, distributed and HA routers)
+var routerInterfaceOwners = map[string]bool{
+	"network:router_interface":               true,
+	"network:router_interface_distributed":   true,
+	"network:ha_router_replicated_interface": true,
+}
+
+//GetPortOwnerClass returns class of port device owner, e.g. all compute:<zone> owners are compute ports and interfaces of distributed and HA routers are router interfaces
+func GetPortOwnerClass(deviceOwner string) string {
+	switch {
+	case deviceOwner == "":
+		return PortOwnerUnbound
+	case strings.HasPrefix(deviceOwner, "compute:"):
+		return PortOwnerCompute
+	case routerInterfaceOwners[deviceOwner]:
+		return PortOwnerRouterInterface
+	case deviceOwner == "network:dhcp":
+		return PortOwnerDHCP
+	case deviceOwner == "network:floatingip":
+		return PortOwnerFloatingIP
+	default:
+		return PortOwnerOther
 	}
+}
 
+//GetPortsBreakdownPerTenant is used to retrieve number of ports per tenant split by status and by class of device owner
+//Ports are retrieved by a single listing
+func GetPortsBreakdownPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (PortCounts, serror.SnapError) {
+	counts := PortCounts{
+		Total:            map[string]int64{},
+		Status:           map[string]map[string]int64{},
+		Owner:            map[string]map[string]int64{},
+		PerNetwork:       map[string]int64{},
+		PerSubnet:        map[string]int64{},
+		RouterInterfaces: map[string]int64{},
+		QoSPolicy:        initTenantCounts(tenantList),
+	}
+	for _, status := range PortStatuses {
+		counts.Status[status] = map[string]int64{}
+	}
+	for _, class := range PortOwnerClasses {
+		counts.Owner[class] = map[string]int64{}
+	}
 	for _, tnt := range tenantList {
-		if _, ok := tenantSubnetsCount[tnt.Name]; !ok {
-			tenantSubnetsCount[tnt.Name] = 0
+		counts.Total[tnt.ID] = 0
+		for _, status := range PortStatuses {
+			counts.Status[status][tnt.ID] = 0
+		}
+		for _, class := range PortOwnerClasses {
+			counts.Owner[class][tnt.ID] = 0
+		}
+	}
+	unlisted := newUnlistedTracker(tenantList)
+
+	err := tenantresources.List(client, tenantresources.Ports, withFields(opts, tenantresources.PortFields)).EachPage(func(page pagination.Page) (bool, error) {
+		ports, err := tenantresources.ExtractPorts(page)
+		if err != nil {
+			return false, err
 		}
 
-		for _, subnet := range subnetList {
-			if tnt.ID == subnet.TenantID {
-				tenantSubnetsCount[tnt.Name]++
+		for _, port := range ports {
+			counts.Total[port.TenantID]++
+			unlisted.add("Total", "", port.ID, port.TenantID)
+			if counts.Status[port.Status] == nil {
+				counts.Status[port.Status] = map[string]int64{}
+			}
+			counts.Status[port.Status][port.TenantID]++
+			unlisted.add("Status", port.Status, port.ID, port.TenantID)
+			class := GetPortOwnerClass(port.DeviceOwner)
+			counts.Owner[class][port.TenantID]++
+			unlisted.add("Owner", class, port.ID, port.TenantID)
+			counts.PerNetwork[port.NetworkID]++
+			for _, fixedIP := range port.FixedIPs {
+				counts.PerSubnet[fixedIP.SubnetID]++
+			}
+			if routerInterfaceOwners[port.DeviceOwner] {
+				counts.RouterInterfaces[port.DeviceID]++
+			}
+			if port.QoSPolicyID != "" {
+				counts.QoSPolicy[port.TenantID]++
+				unlisted.add("QoSPolicy", "", port.ID, port.TenantID)
 			}
 		}
+		return true, nil
+	})
+	if err != nil {
+		return PortCounts{}, redact.New(err, map[string]interface{}{"resource": tenantresources.Ports.Key})
 	}
-	return tenantSubnetsCount, nil
+	counts.Unlisted = unlisted.resources
+	return counts, nil
 }
 
-//GetRoutersCountPerTenant  is used to retrieve number of routers per tenant
-func GetRoutersCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant) (map[string]int64, serror.SnapError) {
-	tenantRoutersCount := map[string]int64{}
+//GetPortsCountPerTenant is used to retrieve number of ports per tenant, counts are keyed by tenant ID
+func GetPortsCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant) (map[string]int64, serror.SnapError) {
+	counts, err := GetPortsBreakdownPerTenant(client, tenantList, nil)
+	if err != nil {
+		return map[string]int64{}, err
+	}
+	return listedTenantCounts(counts.Total, tenantList), nil
+}
 
-	pager := routers.List(client, routers.ListOpts{})
-	page, err := pager.AllPages()
+//FloatingIPStatuses statuses of floating IPs which are counted for every known tenant, even if tenant has no such floating IPs
+var FloatingIPStatuses = []string{"ACTIVE", "DOWN", "ERROR"}
+
+//FloatingIPCounts holds numbers of floating IPs keyed by tenant ID
+type FloatingIPCounts struct {
+	Total        map[string]int64
+	Associated   map[string]int64
+	Unassociated map[string]int64
+	//Status holds numbers of floating IPs keyed by status and tenant ID
+	Status   map[string]map[string]int64
+	Unlisted UnlistedResources
+}
+
+//GetFloatingIPsBreakdownPerTenant is used to retrieve number of floating IPs per tenant split by association with port and by status
+//Floating IP is associated if it has port or fixed IP address
+func GetFloatingIPsBreakdownPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (FloatingIPCounts, serror.SnapError) {
+	counts := FloatingIPCounts{
+		Total:        map[string]int64{},
+		Associated:   map[string]int64{},
+		Unassociated: map[string]int64{},
+		Status:       map[string]map[string]int64{},
+	}
+	for _, status := range FloatingIPStatuses {
+		counts.Status[status] = map[string]int64{}
+	}
+	for _, tnt := range tenantList {
+		counts.Total[tnt.ID] = 0
+		counts.Associated[tnt.ID] = 0
+		counts.Unassociated[tnt.ID] = 0
+		for _, status := range FloatingIPStatuses {
+			counts.Status[status][tnt.ID] = 0
+		}
+	}
+	unlisted := newUnlistedTracker(tenantList)
+
+	err := tenantresources.List(client, tenantresources.FloatingIPs, withFields(opts, tenantresources.FloatingIPFields)).EachPage(func(page pagination.Page) (bool, error) {
+		floatingIPs, err := tenantresources.ExtractFloatingIPs(page)
+		if err != nil {
+			return false, err
+		}
+
+		for _, fip := range floatingIPs {
+			counts.Total[fip.TenantID]++
+			unlisted.add("Total", "", fip.ID, fip.TenantID)
+			if fip.PortID != "" || fip.FixedIP != "" {
+				counts.Associated[fip.TenantID]++
+				unlisted.add("Associated", "", fip.ID, fip.TenantID)
+			} else {
+				counts.Unassociated[fip.TenantID]++
+				unlisted.add("Unassociated", "", fip.ID, fip.TenantID)
+			}
+			if counts.Status[fip.Status] == nil {
+				counts.Status[fip.Status] = map[string]int64{}
+			}
+			counts.Status[fip.Status][fip.TenantID]++
+			unlisted.add("Status", fip.Status, fip.ID, fip.TenantID)
+		}
+		return true, nil
+	})
 	if err != nil {
-		return tenantRoutersCount, serror.New(err)
+		return FloatingIPCounts{}, redact.New(err, map[string]interface{}{"resource": tenantresources.FloatingIPs.Key})
 	}
+	counts.Unlisted = unlisted.resources
+	return counts, nil
+}
 
-	routerList, err := routers.ExtractRouters(page)
+//GetFloatingIPsCountPerTenant is used to retrieve number of floating IPs per tenant, counts are keyed by tenant ID
+func GetFloatingIPsCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant) (map[string]int64, serror.SnapError) {
+	counts, err := GetFloatingIPsBreakdownPerTenant(client, tenantList, nil)
 	if err != nil {
-		return tenantRoutersCount, serror.New(err)
+		return map[string]int64{}, err
 	}
+	return listedTenantCounts(counts.Total, tenantList), nil
+}
 
-	for _, tnt := range tenantList {
-		if _, ok := tenantRoutersCount[tnt.Name]; !ok {
-			tenantRoutersCount[tnt.Name] = 0
+//GetSecurityGroupsCountPerTenant is used to retrieve number of security groups per tenant
+func GetSecurityGroupsCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (TenantCounts, serror.SnapError) {
+	return countPerTenant(client, tenantresources.SecurityGroups, tenantList, opts)
+}
+
+//GetSecurityGroupRulesCountPerTenant is used to retrieve number of security group rules per tenant
+func GetSecurityGroupRulesCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (TenantCounts, serror.SnapError) {
+	return countPerTenant(client, tenantresources.SecurityGroupRules, tenantList, opts)
+}
+
+//RBACObjectTypes types of objects shared by RBAC policies which are counted for every known tenant
+var RBACObjectTypes = []string{"network", "qos_policy", "security_group", "address_scope", "subnetpool", "address_group"}
+
+//RBACActions actions granted by RBAC policies which are counted for every known tenant
+var RBACActions = []string{"access_as_shared", "access_as_external"}
+
+//RBACPolicyCounts holds numbers of RBAC policies keyed by tenant ID
+type RBACPolicyCounts struct {
+	Total map[string]int64
+	//ObjectType holds numbers of RBAC policies keyed by type of shared object and tenant ID
+	ObjectType map[string]map[string]int64
+	//Action holds numbers of RBAC policies keyed by granted action and tenant ID
+	Action   map[string]map[string]int64
+	Unlisted UnlistedResources
+}
+
+//GetRBACPoliciesCountPerTenant is used to retrieve number of RBAC policies per tenant split by type of shared object and by action
+func GetRBACPoliciesCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (RBACPolicyCounts, serror.SnapError) {
+	counts := RBACPolicyCounts{
+		Total:      initTenantCounts(tenantList),
+		ObjectType: map[string]map[string]int64{},
+		Action:     map[string]map[string]int64{},
+	}
+	for _, objectType := range RBACObjectTypes {
+		counts.ObjectType[objectType] = initTenantCounts(tenantList)
+	}
+	for _, action := range RBACActions {
+		counts.Action[action] = initTenantCounts(tenantList)
+	}
+	unlisted := newUnlistedTracker(tenantList)
+
+	err := tenantresources.List(client, tenantresources.RBACPolicies, withFields(opts, tenantresources.RBACPolicyFields)).EachPage(func(page pagination.Page) (bool, error) {
+		policies, err := tenantresources.ExtractRBACPolicies(page)
+		if err != nil {
+			return false, err
 		}
 
-		for _, router := range routerList {
-			if tnt.ID == router.TenantID {
-				tenantRoutersCount[tnt.Name]++
+		for _, policy := range policies {
+			counts.Total[policy.TenantID]++
+			unlisted.add("Total", "", policy.ID, policy.TenantID)
+			if counts.ObjectType[policy.ObjectType] == nil {
+				counts.ObjectType[policy.ObjectType] = map[string]int64{}
+			}
+			counts.ObjectType[policy.ObjectType][policy.TenantID]++
+			unlisted.add("ObjectType", policy.ObjectType, policy.ID, policy.TenantID)
+			if counts.Action[policy.Action] == nil {
+				counts.Action[policy.Action] = map[string]int64{}
 			}
+			counts.Action[policy.Action][policy.TenantID]++
+			unlisted.add("Action", policy.Action, policy.ID, policy.TenantID)
 		}
+		return true, nil
+	})
+	if err != nil {
+		return RBACPolicyCounts{}, redact.New(err, map[string]interface{}{"resource": tenantresources.RBACPolicies.Key})
 	}
-	return tenantRoutersCount, nil
+	counts.Unlisted = unlisted.resources
+	return counts, nil
 }
 
-//GetPortsCountPerTenant  is used to retrieve number of ports per tenant
-func GetPortsCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant) (map[string]int64, serror.SnapError) {
-	tenantPortsCount := map[string]int64{}
+//SubnetPoolUsage describes address space of subnet pool
+type SubnetPoolUsage struct {
+	tenantresources.SubnetPool
+	//Capacity is a number of addresses of pool prefixes
+	Capacity float64
+}
 
-	pager := ports.List(client, ports.ListOpts{})
-	page, err := pager.AllPages()
+//SubnetPoolCounts holds numbers of subnet pools keyed by tenant ID
+type SubnetPoolCounts struct {
+	Total map[string]int64
+	//Pools holds address space of subnet pools keyed by pool ID
+	Pools    map[string]SubnetPoolUsage
+	Unlisted UnlistedResources
+}
+
+//GetSubnetPoolsCountPerTenant is used to retrieve number of subnet pools per tenant and address space of each pool
+//Address space allocated from pools is calculated while subnets are counted, see SubnetCounts
+func GetSubnetPoolsCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (SubnetPoolCounts, serror.SnapError) {
+	counts := SubnetPoolCounts{
+		Total: initTenantCounts(tenantList),
+		Pools: map[string]SubnetPoolUsage{},
+	}
+	unlisted := newUnlistedTracker(tenantList)
+
+	err := tenantresources.List(client, tenantresources.SubnetPools, withFields(opts, tenantresources.SubnetPoolFields)).EachPage(func(page pagination.Page) (bool, error) {
+		subnetPools, err := tenantresources.ExtractSubnetPools(page)
+		if err != nil {
+			return false, err
+		}
+
+		for _, subnetPool := range subnetPools {
+			counts.Total[subnetPool.TenantID]++
+			unlisted.add("Total", "", subnetPool.ID, subnetPool.TenantID)
+			capacity := new(big.Int)
+			for _, prefix := range subnetPool.Prefixes {
+				capacity.Add(capacity, getCIDRSize(prefix))
+			}
+			usage := SubnetPoolUsage{SubnetPool: subnetPool}
+			usage.Capacity, _ = new(big.Float).SetInt(capacity).Float64()
+			counts.Pools[subnetPool.ID] = usage
+		}
+		return true, nil
+	})
 	if err != nil {
-		return tenantPortsCount, serror.New(err)
+		return SubnetPoolCounts{}, redact.New(err, map[string]interface{}{"resource": tenantresources.SubnetPools.Key})
 	}
+	counts.Unlisted = unlisted.resources
+	return counts, nil
+}
 
-	portList, err := ports.ExtractPorts(page)
+//QoSRuleTypes types of QoS rules which are counted for every known tenant
+var QoSRuleTypes = []string{"bandwidth_limit", "dscp_marking", "minimum_bandwidth"}
+
+//QoSPolicyCounts holds numbers of QoS policies and their rules keyed by tenant ID
+type QoSPolicyCounts struct {
+	Total map[string]int64
+	Rules map[string]int64
+	//RuleType holds numbers of QoS rules keyed by type and tenant ID
+	RuleType map[string]map[string]int64
+	//Unlisted holds policies of tenants which are not present in tenant list, also for counters of their rules
+	Unlisted UnlistedResources
+}
+
+//GetQoSPoliciesCountPerTenant is used to retrieve number of QoS policies per tenant and number of their rules split by type
+//Rules are counted from policies listing, they are owned by tenant of policy
+func GetQoSPoliciesCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (QoSPolicyCounts, serror.SnapError) {
+	counts := QoSPolicyCounts{
+		Total:    initTenantCounts(tenantList),
+		Rules:    initTenantCounts(tenantList),
+		RuleType: map[string]map[string]int64{},
+	}
+	for _, ruleType := range QoSRuleTypes {
+		counts.RuleType[ruleType] = initTenantCounts(tenantList)
+	}
+	unlisted := newUnlistedTracker(tenantList)
+
+	err := tenantresources.List(client, tenantresources.QoSPolicies, withFields(opts, tenantresources.QoSPolicyFields)).EachPage(func(page pagination.Page) (bool, error) {
+		policies, err := tenantresources.ExtractQoSPolicies(page)
+		if err != nil {
+			return false, err
+		}
+
+		for _, policy := range policies {
+			counts.Total[policy.TenantID]++
+			unlisted.add("Total", "", policy.ID, policy.TenantID)
+			for _, rule := range policy.Rules {
+				counts.Rules[policy.TenantID]++
+				unlisted.add("Rules", "", policy.ID, policy.TenantID)
+				if counts.RuleType[rule.Type] == nil {
+					counts.RuleType[rule.Type] = map[string]int64{}
+				}
+				counts.RuleType[rule.Type][policy.TenantID]++
+				unlisted.add("RuleType", rule.Type, policy.ID, policy.TenantID)
+			}
+		}
+		return true, nil
+	})
 	if err != nil {
-		return tenantPortsCount, serror.New(err)
+		return QoSPolicyCounts{}, redact.New(err, map[string]interface{}{"resource": tenantresources.QoSPolicies.Key})
 	}
+	counts.Unlisted = unlisted.resources
+	return counts, nil
+}
 
-	for _, tnt := range tenantList {
-		if _, ok := tenantPortsCount[tnt.Name]; !ok {
-			tenantPortsCount[tnt.Name] = 0
+//IsExtensionAvailable is used to check by extension discovery whether Neutron extension with given alias is loaded
+func IsExtensionAvailable(client *gophercloud.ServiceClient, alias string) (bool, serror.SnapError) {
+	_, err := extensions.Get(client, alias).Extract()
+	if err == nil {
+		return true, nil
+	}
+	if respErr, ok := err.(*gophercloud.UnexpectedResponseCodeError); ok && respErr.Actual == http.StatusNotFound {
+		return false, nil
+	}
+	return false, redact.New(err, map[string]interface{}{"extension": alias})
+}
+
+//GetFirewallPoliciesCountPerTenant is used to retrieve number of firewall policies per tenant
+func GetFirewallPoliciesCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (TenantCounts, serror.SnapError) {
+	return countPerTenant(client, tenantresources.FirewallPolicies, tenantList, opts)
+}
+
+//GetFirewallRulesCountPerTenant is used to retrieve number of firewall rules per tenant
+func GetFirewallRulesCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (TenantCounts, serror.SnapError) {
+	return countPerTenant(client, tenantresources.FirewallRules, tenantList, opts)
+}
+
+//FirewallGroupStatuses statuses of firewall groups which are counted for every known tenant,
+//transitional PENDING_CREATE, PENDING_UPDATE and PENDING_DELETE statuses are counted as PENDING
+var FirewallGroupStatuses = []string{"ACTIVE", "INACTIVE", "DOWN", "ERROR", "PENDING"}
+
+//FirewallGroupCounts holds numbers of firewall groups keyed by tenant ID
+type FirewallGroupCounts struct {
+	Total map[string]int64
+	//Status holds numbers of firewall groups keyed by status and tenant ID
+	Status map[string]map[string]int64
+	//Ports holds numbers of ports bound to firewall groups keyed by tenant ID
+	Ports    map[string]int64
+	Unlisted UnlistedResources
+}
+
+//GetFirewallGroupsBreakdownPerTenant is used to retrieve number of firewall groups per tenant split by status together with number of ports bound to them
+func GetFirewallGroupsBreakdownPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (FirewallGroupCounts, serror.SnapError) {
+	counts := FirewallGroupCounts{
+		Total:  initTenantCounts(tenantList),
+		Status: map[string]map[string]int64{},
+		Ports:  initTenantCounts(tenantList),
+	}
+	for _, status := range FirewallGroupStatuses {
+		counts.Status[status] = initTenantCounts(tenantList)
+	}
+	unlisted := newUnlistedTracker(tenantList)
+
+	err := tenantresources.List(client, tenantresources.FirewallGroups, withFields(opts, tenantresources.FirewallGroupFields)).EachPage(func(page pagination.Page) (bool, error) {
+		firewallGroups, err := tenantresources.ExtractFirewallGroups(page)
+		if err != nil {
+			return false, err
 		}
 
-		for _, port := range portList {
-			if tnt.ID == port.TenantID {
-				tenantPortsCount[tnt.Name]++
+		for _, firewallGroup := range firewallGroups {
+			counts.Total[firewallGroup.TenantID]++
+			unlisted.add("Total", "", firewallGroup.ID, firewallGroup.TenantID)
+			counts.Ports[firewallGroup.TenantID] += int64(len(firewallGroup.Ports))
+			if len(firewallGroup.Ports) > 0 {
+				unlisted.add("Ports", "", firewallGroup.ID, firewallGroup.TenantID)
+			}
+
+			status := firewallGroup.Status
+			if strings.HasPrefix(status, "PENDING_") {
+				status = "PENDING"
 			}
+			if counts.Status[status] == nil {
+				counts.Status[status] = map[string]int64{}
+			}
+			counts.Status[status][firewallGroup.TenantID]++
+			unlisted.add("Status", status, firewallGroup.ID, firewallGroup.TenantID)
 		}
+		return true, nil
+	})
+	if err != nil {
+		return FirewallGroupCounts{}, redact.New(err, map[string]interface{}{"resource": tenantresources.FirewallGroups.Key})
 	}
-	return tenantPortsCount, nil
+	counts.Unlisted = unlisted.resources
+	return counts, nil
 }
 
-//GetFloatingIPsCountPerTenant is used to retrieve number of floating IPs per tenant
-func GetFloatingIPsCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant) (map[string]int64, serror.SnapError) {
-	tenantFloatingipsCount := map[string]int64{}
+//GetIKEPoliciesCountPerTenant is used to retrieve number of VPN IKE policies per tenant
+func GetIKEPoliciesCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (TenantCounts, serror.SnapError) {
+	return countPerTenant(client, tenantresources.IKEPolicies, tenantList, opts)
+}
 
-	opts := floatingips.ListOpts{}
-	pager := floatingips.List(client, opts)
+//GetIPsecPoliciesCountPerTenant is used to retrieve number of VPN IPsec policies per tenant
+func GetIPsecPoliciesCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (TenantCounts, serror.SnapError) {
+	return countPerTenant(client, tenantresources.IPsecPolicies, tenantList, opts)
+}
 
-	page, err := pager.AllPages()
+//GetVPNServicesCountPerTenant is used to retrieve number of VPN services per tenant
+func GetVPNServicesCountPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (TenantCounts, serror.SnapError) {
+	return countPerTenant(client, tenantresources.VPNServices, tenantList, opts)
+}
+
+//IPsecSiteConnectionStatuses statuses of IPsec site connections which are counted for every known tenant, even if tenant has no such connections
+var IPsecSiteConnectionStatuses = []string{"ACTIVE", "DOWN", "ERROR"}
+
+//IPsecSiteConnectionCounts holds numbers of IPsec site connections keyed by tenant ID
+type IPsecSiteConnectionCounts struct {
+	Total map[string]int64
+	//Status holds numbers of IPsec site connections keyed by status and tenant ID
+	Status   map[string]map[string]int64
+	Unlisted UnlistedResources
+}
+
+//GetIPsecSiteConnectionsBreakdownPerTenant is used to retrieve number of IPsec site connections per tenant split by status
+func GetIPsecSiteConnectionsBreakdownPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts) (IPsecSiteConnectionCounts, serror.SnapError) {
+	counts := IPsecSiteConnectionCounts{
+		Total:  initTenantCounts(tenantList),
+		Status: map[string]map[string]int64{},
+	}
+	for _, status := range IPsecSiteConnectionStatuses {
+		counts.Status[status] = initTenantCounts(tenantList)
+	}
+	unlisted := newUnlistedTracker(tenantList)
+
+	err := tenantresources.List(client, tenantresources.IPsecSiteConnections, withFields(opts, tenantresources.IPsecSiteConnectionFields)).EachPage(func(page pagination.Page) (bool, error) {
+		connections, err := tenantresources.ExtractIPsecSiteConnections(page)
+		if err != nil {
+			return false, err
+		}
+
+		for _, connection := range connections {
+			counts.Total[connection.TenantID]++
+			unlisted.add("Total", "", connection.ID, connection.TenantID)
+			if counts.Status[connection.Status] == nil {
+				counts.Status[connection.Status] = map[string]int64{}
+			}
+			counts.Status[connection.Status][connection.TenantID]++
+			unlisted.add("Status", connection.Status, connection.ID, connection.TenantID)
+		}
+		return true, nil
+	})
 	if err != nil {
-		return tenantFloatingipsCount, serror.New(err)
+		return IPsecSiteConnectionCounts{}, redact.New(err, map[string]interface{}{"resource": tenantresources.IPsecSiteConnections.Key})
 	}
+	counts.Unlisted = unlisted.resources
+	return counts, nil
+}
+
+//TenantCounts holds numbers of resources keyed by tenant ID
+type TenantCounts struct {
+	Total    map[string]int64
+	Unlisted UnlistedResources
+}
 
-	floatingipList, err := floatingips.ExtractFloatingIPs(page)
+//countPerTenant is used to retrieve number of resources of given collection per tenant, counts are keyed by tenant ID
+//Resources are streamed page by page and counted in a single pass, so the whole collection is never kept in memory
+//Resources owned by tenants which are not on the list (e.g. deleted projects) are counted too, so the map covers whole collection
+func countPerTenant(client *gophercloud.ServiceClient, resource tenantresources.Resource, tenantList []types.Tenant, opts *tenantresources.ListOpts) (TenantCounts, serror.SnapError) {
+	tenantCount := initTenantCounts(tenantList)
+	unlisted := newUnlistedTracker(tenantList)
+
+	err := tenantresources.List(client, resource, opts).EachPage(func(page pagination.Page) (bool, error) {
+		resources, err := tenantresources.ExtractTenantResources(page)
+		if err != nil {
+			return false, err
+		}
+
+		for _, r := range resources {
+			tenantCount[r.TenantID]++
+			unlisted.add("Total", "", r.ID, r.TenantID)
+		}
+		return true, nil
+	})
 	if err != nil {
-		return tenantFloatingipsCount, serror.New(err)
+		return TenantCounts{}, redact.New(err, map[string]interface{}{"resource": resource.Key})
+	}
+	return TenantCounts{Total: tenantCount, Unlisted: unlisted.resources}, nil
+}
+
+//UnlistedResources holds counted resources owned by tenants which are not present in tenant list (e.g. deleted projects) keyed by counter,
+//counter is a name of field of counts optionally followed by key of nested counts, e.g. "Total" or "Status/ACTIVE"
+//Only IDs and tenant IDs are kept, so that resources can be logged without listing them again
+type UnlistedResources map[string][]tenantresources.TenantResource
+
+//Counted returns unlisted resources counted by given field of counts, key selects nested counts and is empty for flat ones
+func (u UnlistedResources) Counted(field string, key string) []tenantresources.TenantResource {
+	return u[getCounterName(field, key)]
+}
+
+//getCounterName returns name of counter under which unlisted resources are kept
+func getCounterName(field string, key string) string {
+	if key == "" {
+		return field
 	}
+	return field + "/" + key
+}
 
+//unlistedTracker records counted resources owned by tenants which are not present in tenant list, resources without tenant are skipped
+type unlistedTracker struct {
+	listed    map[string]bool
+	resources UnlistedResources
+}
+
+//newUnlistedTracker returns tracker of resources owned by tenants which are not present in tenantList
+func newUnlistedTracker(tenantList []types.Tenant) unlistedTracker {
+	t := unlistedTracker{listed: map[string]bool{}, resources: UnlistedResources{}}
+	for _, tnt := range tenantList {
+		t.listed[tnt.ID] = true
+	}
+	return t
+}
+
+//add records resource counted by field of counts, key selects nested counts and is empty for flat ones
+func (t unlistedTracker) add(field string, key string, id string, tenantID string) {
+	if tenantID == "" || t.listed[tenantID] {
+		return
+	}
+	counter := getCounterName(field, key)
+	t.resources[counter] = append(t.resources[counter], tenantresources.TenantResource{ID: id, TenantID: tenantID})
+}
+
+//initTenantCounts returns counts of resources with zero for every known tenant
+func initTenantCounts(tenantList []types.Tenant) map[string]int64 {
+	tenantCount := map[string]int64{}
 	for _, tnt := range tenantList {
-		if _, ok := tenantFloatingipsCount[tnt.Name]; !ok {
-			tenantFloatingipsCount[tnt.Name] = 0
+		tenantCount[tnt.ID] = 0
+	}
+	return tenantCount
+}
+
+//listedTenantCounts returns counts of resources of given tenants only, counts of unlisted tenants are dropped
+func listedTenantCounts(counts map[string]int64, tenantList []types.Tenant) map[string]int64 {
+	tenantCount := initTenantCounts(tenantList)
+	for tenantID := range tenantCount {
+		tenantCount[tenantID] = counts[tenantID]
+	}
+	return tenantCount
+}
+
+//withFields returns copy of options with fields replaced by given ones, if options limit attributes of resources
+//Attributes other than IDs and tenant IDs are needed to split resources, so they are retrieved even if only tenant IDs are requested
+func withFields(opts *tenantresources.ListOpts, fields []string) *tenantresources.ListOpts {
+	if opts == nil || len(opts.Fields) == 0 {
+		return opts
+	}
+	fieldsOpts := *opts
+	fieldsOpts.Fields = fields
+	return &fieldsOpts
+}
+
+//GetAgents is used to retrieve state of all Neutron agents, it requires admin role
+func GetAgents(client *gophercloud.ServiceClient) ([]agents.Agent, serror.SnapError) {
+	agentList, err := agents.List(client).Extract()
+	if err != nil {
+		return nil, redact.New(err, map[string]interface{}{"resource": "agents"})
+	}
+	return agentList, nil
+}
+
+//LBProvisioningStatuses provisioning statuses of load balancing resources which are counted for every known tenant,
+//transitional PENDING_CREATE, PENDING_UPDATE and PENDING_DELETE statuses are counted as PENDING
+var LBProvisioningStatuses = []string{"ACTIVE", "PENDING", "ERROR"}
+
+//LBOperatingStatuses operating statuses of load balancing resources which are counted for every known tenant
+var LBOperatingStatuses = []string{"ONLINE", "OFFLINE", "DEGRADED", "ERROR", "NO_MONITOR"}
+
+//LBResourceCounts holds numbers of load balancing resources of one collection keyed by tenant ID
+type LBResourceCounts struct {
+	Total map[string]int64
+	//ProvisioningStatus holds numbers of resources keyed by provisioning status and tenant ID
+	ProvisioningStatus map[string]map[string]int64
+	//OperatingStatus holds numbers of resources keyed by operating status and tenant ID
+	OperatingStatus map[string]map[string]int64
+	Unlisted        UnlistedResources
+	unlisted        unlistedTracker
+}
+
+//LoadBalancerCounts holds numbers of load balancing resources keyed by name of collection (loadbalancers, listeners, pools, members, healthmonitors)
+type LoadBalancerCounts map[string]LBResourceCounts
+
+//newLBResourceCounts returns counts of load balancing resources with zero for every known tenant and status
+func newLBResourceCounts(tenantList []types.Tenant) LBResourceCounts {
+	counts := LBResourceCounts{
+		Total:              initTenantCounts(tenantList),
+		ProvisioningStatus: map[string]map[string]int64{},
+		OperatingStatus:    map[string]map[string]int64{},
+		unlisted:           newUnlistedTracker(tenantList),
+	}
+	counts.Unlisted = counts.unlisted.resources
+	for _, status := range LBProvisioningStatuses {
+		counts.ProvisioningStatus[status] = initTenantCounts(tenantList)
+	}
+	for _, status := range LBOperatingStatuses {
+		counts.OperatingStatus[status] = initTenantCounts(tenantList)
+	}
+	return counts
+}
+
+//add counts load balancing resource for its owner
+func (c LBResourceCounts) add(r loadbalancers.LBResource) {
+	owner := r.Owner()
+	c.Total[owner]++
+	c.unlisted.add("Total", "", r.ID, owner)
+
+	provisioningStatus := r.ProvisioningStatus
+	if strings.HasPrefix(provisioningStatus, "PENDING_") {
+		provisioningStatus = "PENDING"
+	}
+	if provisioningStatus != "" {
+		if c.ProvisioningStatus[provisioningStatus] == nil {
+			c.ProvisioningStatus[provisioningStatus] = map[string]int64{}
+		}
+		c.ProvisioningStatus[provisioningStatus][owner]++
+		c.unlisted.add("ProvisioningStatus", provisioningStatus, r.ID, owner)
+	}
+	if r.OperatingStatus != "" {
+		if c.OperatingStatus[r.OperatingStatus] == nil {
+			c.OperatingStatus[r.OperatingStatus] = map[string]int64{}
+		}
+		c.OperatingStatus[r.OperatingStatus][owner]++
+		c.unlisted.add("OperatingStatus", r.OperatingStatus, r.ID, owner)
+	}
+}
+
+//GetLoadBalancersBreakdownPerTenant is used to retrieve number of load balancers, listeners, pools, members and health monitors per tenant
+//split by provisioning and operating status, members are listed separately for every pool which has any
+//Members are listed by at most maxConcurrent parallel requests, pools for which listing failed are skipped and their errors are returned per pool ID
+func GetLoadBalancersBreakdownPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, opts *tenantresources.ListOpts, maxConcurrent int) (LoadBalancerCounts, map[string]serror.SnapError, serror.SnapError) {
+	counts := LoadBalancerCounts{}
+	for _, resource := range []tenantresources.Resource{loadbalancers.LoadBalancers, loadbalancers.Listeners, loadbalancers.HealthMonitors} {
+		resourceCounts := newLBResourceCounts(tenantList)
+		err := loadbalancers.List(client, resource, withFields(opts, loadbalancers.StatusFields)).EachPage(func(page pagination.Page) (bool, error) {
+			resources, err := loadbalancers.ExtractLBResources(page)
+			if err != nil {
+				return false, err
+			}
+
+			for _, r := range resources {
+				resourceCounts.add(r)
+			}
+			return true, nil
+		})
+		if err != nil {
+			return LoadBalancerCounts{}, nil, redact.New(err, map[string]interface{}{"resource": resource.Key})
+		}
+		counts[resource.Key] = resourceCounts
+	}
+
+	poolCounts := newLBResourceCounts(tenantList)
+	poolsWithMembers := []string{}
+	err := loadbalancers.List(client, loadbalancers.Pools, withFields(opts, loadbalancers.PoolFields)).EachPage(func(page pagination.Page) (bool, error) {
+		pools, err := loadbalancers.ExtractPools(page)
+		if err != nil {
+			return false, err
 		}
 
-		for _, floatip := range floatingipList {
-			if tnt.ID == floatip.TenantID {
-				tenantFloatingipsCount[tnt.Name]++
+		for _, pool := range pools {
+			poolCounts.add(pool.LBResource)
+			if len(pool.Members) > 0 {
+				poolsWithMembers = append(poolsWithMembers, pool.ID)
 			}
 		}
+		return true, nil
+	})
+	if err != nil {
+		return LoadBalancerCounts{}, nil, redact.New(err, map[string]interface{}{"resource": loadbalancers.Pools.Key})
 	}
-	return tenantFloatingipsCount, nil
+	counts[loadbalancers.Pools.Key] = poolCounts
+
+	memberCounts := newLBResourceCounts(tenantList)
+	var mutex sync.Mutex
+	poolErrors := forEachID(poolsWithMembers, maxConcurrent, func(poolID string) serror.SnapError {
+		poolMembers := []loadbalancers.LBResource{}
+		err := loadbalancers.List(client, loadbalancers.Members(poolID), withFields(opts, loadbalancers.StatusFields)).EachPage(func(page pagination.Page) (bool, error) {
+			members, err := loadbalancers.ExtractLBResources(page)
+			if err != nil {
+				return false, err
+			}
+
+			poolMembers = append(poolMembers, members...)
+			return true, nil
+		})
+		if err != nil {
+			return redact.New(err, map[string]interface{}{"resource": loadbalancers.MembersKey, "poolID": poolID})
+		}
+
+		mutex.Lock()
+		for _, member := range poolMembers {
+			memberCounts.add(member)
+		}
+		mutex.Unlock()
+		return nil
+	})
+	counts[loadbalancers.MembersKey] = memberCounts
+	return counts, poolErrors, nil
 }
 
-//GetQuotasPerTenant is used to retrieve quotas per tenants
-func GetQuotasPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant) (map[string]map[string]int64, serror.SnapError) {
-	var tenantQuotas map[string]map[string]int64
-	tenantQuotas = make(map[string]map[string]int64)
+//GetLoadBalancerQuotasPerTenant is used to retrieve quotas of load balancing resources per tenants from Octavia, quotas are keyed by tenant ID
+//Quotas are retrieved by at most maxConcurrent parallel requests, tenants for which retrieval failed are skipped and their errors are returned per tenant ID
+func GetLoadBalancerQuotasPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, maxConcurrent int) (map[string]map[string]int64, map[string]serror.SnapError) {
+	tenantQuotas := map[string]map[string]int64{}
+	var mutex sync.Mutex
 
-	for _, tnt := range tenantList {
+	tenantErrors := forEachTenant(tenantList, maxConcurrent, func(tnt types.Tenant) serror.SnapError {
+		quotasMap, err := loadbalancers.GetQuotas(client, tnt.ID).Extract()
+		if err != nil {
+			return redact.New(err)
+		}
+
+		mutex.Lock()
+		tenantQuotas[tnt.ID] = quotasMap
+		mutex.Unlock()
+		return nil
+	})
+	return tenantQuotas, tenantErrors
+}
+
+//GetNetworkIPAvailabilities is used to retrieve number of allocatable and used IP addresses of all networks and their subnets
+//Nil is returned if Neutron does not provide network IP availability extension or does not allow to use it, then availability can be calculated by CalculateNetworkIPAvailabilities
+func GetNetworkIPAvailabilities(client *gophercloud.ServiceClient) ([]ipavailability.NetworkIPAvailability, serror.SnapError) {
+	availabilities, err := ipavailability.List(client).Extract()
+	if err == nil {
+		return availabilities, nil
+	}
+	if respErr, ok := err.(*gophercloud.UnexpectedResponseCodeError); ok && (respErr.Actual == http.StatusNotFound || respErr.Actual == http.StatusForbidden) {
+		return nil, nil
+	}
+	return nil, redact.New(err, map[string]interface{}{"resource": "network_ip_availabilities"})
+}
+
+//CalculateNetworkIPAvailabilities calculates number of allocatable and used IP addresses of networks based on allocation pools of their subnets and fixed IPs of ports
+//Networks and their subnets are ordered by ID
+func CalculateNetworkIPAvailabilities(networks NetworkCounts, subnets SubnetCounts, ports PortCounts) []ipavailability.NetworkIPAvailability {
+	networkSubnets := map[string][]string{}
+	for id, subnet := range subnets.Subnets {
+		networkSubnets[subnet.NetworkID] = append(networkSubnets[subnet.NetworkID], id)
+	}
+
+	networkIDs := []string{}
+	for id := range networks.Networks {
+		networkIDs = append(networkIDs, id)
+	}
+	sort.Strings(networkIDs)
+
+	availabilities := []ipavailability.NetworkIPAvailability{}
+	for _, id := range networkIDs {
+		network := networks.Networks[id]
+		availability := ipavailability.NetworkIPAvailability{
+			NetworkID:   network.ID,
+			NetworkName: network.Name,
+			TenantID:    network.TenantID,
+			Subnets:     []ipavailability.SubnetIPAvailability{},
+		}
+
+		sort.Strings(networkSubnets[id])
+		for _, subnetID := range networkSubnets[id] {
+			subnet := subnets.Subnets[subnetID]
+			subnetAvailability := ipavailability.SubnetIPAvailability{
+				SubnetID:   subnet.ID,
+				SubnetName: subnet.Name,
+				CIDR:       subnet.CIDR,
+				IPVersion:  subnet.IPVersion,
+				TotalIPs:   getAllocatableIPs(subnet),
+				UsedIPs:    float64(ports.PerSubnet[subnet.ID]),
+			}
+			availability.Subnets = append(availability.Subnets, subnetAvailability)
+			availability.TotalIPs += subnetAvailability.TotalIPs
+			availability.UsedIPs += subnetAvailability.UsedIPs
+		}
+		availabilities = append(availabilities, availability)
+	}
+	return availabilities
+}
+
+//getAllocatableIPs returns number of addresses in allocation pools of subnet or, if subnet has no pools, number of usable addresses of its CIDR
+func getAllocatableIPs(subnet tenantresources.Subnet) float64 {
+	total := new(big.Int)
+	for _, pool := range subnet.AllocationPools {
+		start, end := net.ParseIP(pool.Start), net.ParseIP(pool.End)
+		if start == nil || end == nil {
+			continue
+		}
+		size := new(big.Int).Sub(ipToInt(end), ipToInt(start))
+		if size.Sign() < 0 {
+			continue
+		}
+		total.Add(total, size.Add(size, big.NewInt(1)))
+	}
+
+	if len(subnet.AllocationPools) == 0 {
+		_, cidr, err := net.ParseCIDR(subnet.CIDR)
+		if err != nil {
+			return 0
+		}
+		_, bits := cidr.Mask.Size()
+		total = getCIDRSize(subnet.CIDR)
+		// network and broadcast addresses of IPv4 subnet and subnet-router anycast address of IPv6 subnet are not allocatable
+		reserved := int64(1)
+		if bits == 8*net.IPv4len {
+			reserved = 2
+		}
+		if total.Cmp(big.NewInt(reserved)) > 0 {
+			total.Sub(total, big.NewInt(reserved))
+		}
+	}
+
+	f, _ := new(big.Float).SetInt(total).Float64()
+	return f
+}
+
+//getCIDRSize returns number of addresses of CIDR, zero if CIDR is not valid
+func getCIDRSize(cidr string) *big.Int {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return new(big.Int)
+	}
+	ones, bits := ipNet.Mask.Size()
+	return new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+}
+
+//ipToInt converts IP address to integer
+func ipToInt(ip net.IP) *big.Int {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	return new(big.Int).SetBytes(ip)
+}
+
+//GetQuotasPerTenant is used to retrieve quotas per tenants, quotas are keyed by tenant ID
+//Quotas are retrieved by at most maxConcurrent parallel requests, tenants for which retrieval failed are skipped and their errors are returned per tenant ID
+func GetQuotasPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, maxConcurrent int) (map[string]map[string]int64, map[string]serror.SnapError) {
+	tenantQuotas := map[string]map[string]int64{}
+	var mutex sync.Mutex
+
+	tenantErrors := forEachTenant(tenantList, maxConcurrent, func(tnt types.Tenant) serror.SnapError {
 		quotasMap, serr := GetQuotasForTenant(client, tnt.ID)
 		if serr != nil {
-			return tenantQuotas, serr
+			return serr
 		}
-		tenantQuotas[tnt.Name] = quotasMap
-	}
-	return tenantQuotas, nil
+
+		mutex.Lock()
+		tenantQuotas[tnt.ID] = quotasMap
+		mutex.Unlock()
+		return nil
+	})
+	return tenantQuotas, tenantErrors
 }
 
 //GetQuotasForTenant is used to retrieve quotas for specified tenant
 func GetQuotasForTenant(client *gophercloud.ServiceClient, tenantID string) (map[string]int64, serror.SnapError) {
 	quotas, err := tenantquotas.Get(client, tenantID).Extract()
 	if err != nil {
-		return nil, serror.New(err)
+		return nil, redact.New(err)
 	}
 
 	quotasMap, ok := quotas[quotaPath]
 	if !ok {
 		f := map[string]interface{}{"quotas": quotas, "tenantName": tenantID}
-		return nil, serror.New(fmt.Errorf("GetQuotasForTenant: inocorrect response format"), f)
+		return nil, redact.New(fmt.Errorf("GetQuotasForTenant: inocorrect response format"), f)
 	}
 	return quotasMap, nil
 }
+
+//GetQuotaDetailsPerTenant is used to retrieve quota details (limit, used and reserved) per tenants, details are keyed by tenant ID
+//It returns nil map when quota details extension is not available, availability is probed with the first tenant before remaining ones are requested
+//Details are retrieved by at most maxConcurrent parallel requests, tenants for which retrieval failed are skipped and their errors are returned per tenant ID
+func GetQuotaDetailsPerTenant(client *gophercloud.ServiceClient, tenantList []types.Tenant, maxConcurrent int) (map[string]map[string]tenantquotas.QuotaDetails, map[string]serror.SnapError) {
+	tenantQuotaDetails := map[string]map[string]tenantquotas.QuotaDetails{}
+	tenantErrors := map[string]serror.SnapError{}
+	if len(tenantList) == 0 {
+		return tenantQuotaDetails, tenantErrors
+	}
+
+	probe := tenantList[0]
+	detailsMap, serr := GetQuotaDetailsForTenant(client, probe.ID)
+	switch {
+	case serr != nil:
+		serr.SetFields(withTenant(serr.Fields(), probe))
+		tenantErrors[probe.ID] = serr
+	case detailsMap == nil:
+		return nil, nil
+	default:
+		tenantQuotaDetails[probe.ID] = detailsMap
+	}
+
+	extensionAvailable := true
+	var mutex sync.Mutex
+	remainingErrors := forEachTenant(tenantList[1:], maxConcurrent, func(tnt types.Tenant) serror.SnapError {
+		detailsMap, serr := GetQuotaDetailsForTenant(client, tnt.ID)
+		if serr != nil {
+			return serr
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		if detailsMap == nil {
+			extensionAvailable = false
+			return nil
+		}
+		tenantQuotaDetails[tnt.ID] = detailsMap
+		return nil
+	})
+
+	if !extensionAvailable {
+		return nil, nil
+	}
+	for id, serr := range remainingErrors {
+		tenantErrors[id] = serr
+	}
+	return tenantQuotaDetails, tenantErrors
+}
+
+//GetQuotaDetailsForTenant is used to retrieve quota details (limit, used and reserved) for specified tenant
+//It returns nil map when quota details extension is not available
+func GetQuotaDetailsForTenant(client *gophercloud.ServiceClient, tenantID string) (map[string]tenantquotas.QuotaDetails, serror.SnapError) {
+	details, err := tenantquotas.GetDetails(client, tenantID).Extract()
+	if err != nil {
+		if respErr, ok := err.(*gophercloud.UnexpectedResponseCodeError); ok && respErr.Actual == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, redact.New(err)
+	}
+
+	detailsMap, ok := details[quotaPath]
+	if !ok {
+		f := map[string]interface{}{"quotas": details, "tenantID": tenantID}
+		return nil, redact.New(fmt.Errorf("GetQuotaDetailsForTenant: incorrect response format"), f)
+	}
+	return detailsMap, nil
+}
+
+//GetQuotaLimits is used to retrieve quotas per tenants out of quota details
+func GetQuotaLimits(tenantQuotaDetails map[string]map[string]tenantquotas.QuotaDetails) map[string]map[string]int64 {
+	tenantQuotas := map[string]map[string]int64{}
+
+	for tnt, detailsMap := range tenantQuotaDetails {
+		tenantQuotas[tnt] = map[string]int64{}
+		for k, details := range detailsMap {
+			tenantQuotas[tnt][k] = details.Limit
+		}
+	}
+	return tenantQuotas
+}
+
+//forEachTenant calls f for each tenant using at most maxConcurrent goroutines
+//Failure for one tenant does not stop processing of remaining ones, errors are returned per tenant ID
+func forEachTenant(tenantList []types.Tenant, maxConcurrent int, f func(tnt types.Tenant) serror.SnapError) map[string]serror.SnapError {
+	tenants := map[string]types.Tenant{}
+	tenantIDs := []string{}
+	for _, tnt := range tenantList {
+		tenants[tnt.ID] = tnt
+		tenantIDs = append(tenantIDs, tnt.ID)
+	}
+
+	return forEachID(tenantIDs, maxConcurrent, func(tenantID string) serror.SnapError {
+		tnt := tenants[tenantID]
+		serr := f(tnt)
+		if serr != nil {
+			serr.SetFields(withTenant(serr.Fields(), tnt))
+		}
+		return serr
+	})
+}
+
+//forEachID calls f for each ID using at most maxConcurrent goroutines
+//Failure for one ID does not stop processing of remaining ones, errors are returned per ID
+func forEachID(ids []string, maxConcurrent int, f func(id string) serror.SnapError) map[string]serror.SnapError {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+
+	idErrors := map[string]serror.SnapError{}
+	var mutex sync.Mutex
+	var done sync.WaitGroup
+
+	queue := make(chan string)
+	for i := 0; i < maxConcurrent && i < len(ids); i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			for id := range queue {
+				serr := f(id)
+				if serr == nil {
+					continue
+				}
+
+				mutex.Lock()
+				idErrors[id] = serr
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	for _, id := range ids {
+		queue <- id
+	}
+	close(queue)
+	done.Wait()
+
+	return idErrors
+}
+
+//withTenant returns copy of error fields extended with tenant name and ID
+func withTenant(fields map[string]interface{}, tnt types.Tenant) map[string]interface{} {
+	f := map[string]interface{}{}
+	for k, v := range fields {
+		f[k] = v
+	}
+	f["tenantName"] = tnt.Name
+	f["tenantID"] = tnt.ID
+	return f
+}
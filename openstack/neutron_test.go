@@ -22,14 +22,21 @@ limitations under the License.
 package openstack
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	openstackgophercloud "github.com/rackspace/gophercloud/openstack"
 	th "github.com/rackspace/gophercloud/testhelper"
 
+	"github.com/intelsdi-x/snap-plugin-collector-neutron/openstack/tenantresources"
 	"github.com/intelsdi-x/snap-plugin-collector-neutron/types"
+	"github.com/intelsdi-x/snap/core/serror"
 	"github.com/rackspace/gophercloud"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/suite"
@@ -45,13 +52,26 @@ func (s *TestSuite) SetupSuite() {
 	th.SetupHTTP()
 	registerRoot()
 	registerAuthentication(s)
+	registerAuthenticationV3(s)
 	registerTenants(s)
+	registerProjects(s)
 	registerNetworks(s)
 	registerSubnets(s)
 	registerRouters(s)
 	registerPorts(s)
 	registerFloatingIPs(s)
+	registerSecurityGroups(s)
+	registerAgents(s)
+	registerLoadBalancers(s)
+	registerLoadBalancerQuotas(s)
+	registerIPsecSiteConnections(s)
+	registerRBACPolicies(s)
+	registerSubnetPools(s)
+	registerQoSPolicies(s)
+	registerFWaaS(s)
+	registerSecurityGroupRules(s)
 	registerQuotas(s)
+	registerQuotaDetails(s)
 }
 
 func (suite *TestSuite) TearDownSuite() {
@@ -89,6 +109,131 @@ func (s *TestSuite) TestGetAllTenants() {
 	})
 }
 
+func (s *TestSuite) TestAuthenticateWithApplicationCredential() {
+	Convey("Given authentication with application credential is requested", s.T(), func() {
+
+		Convey("When application credential ID and secret are provided", func() {
+			provider, serr := AuthenticateWithApplicationCredential(th.Endpoint()+"v3/", "app-id", "", "secret", "", "", "")
+
+			Convey("Then authenticated provider client is returned", func() {
+				So(serr, ShouldBeNil)
+				So(provider.TokenID, ShouldEqual, s.Token)
+			})
+
+			Convey("and network service endpoint is available", func() {
+				networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+				So(err, ShouldBeNil)
+				So(networkClient.Endpoint, ShouldEqual, s.NetworkServiceEndpoint)
+			})
+		})
+
+		Convey("When application credential name, user and secret are provided with unversioned URL", func() {
+			provider, serr := AuthenticateWithApplicationCredential(th.Endpoint(), "", "app-name", "secret", "me", "Default", "")
+
+			Convey("Then authenticated provider client is returned", func() {
+				So(serr, ShouldBeNil)
+				So(provider.TokenID, ShouldEqual, s.Token)
+			})
+
+			Convey("and Identity API v3 is used", func() {
+				identityClient, serr := NewIdentityClient(provider)
+				So(serr, ShouldBeNil)
+				So(isIdentityV3(identityClient), ShouldBeTrue)
+			})
+		})
+
+		Convey("When incorrect secret is provided", func() {
+			provider, serr := AuthenticateWithApplicationCredential(th.Endpoint()+"v3/", "app-id", "", "wrong", "", "", "")
+
+			Convey("Then error is reported", func() {
+				So(serr, ShouldNotBeNil)
+				So(provider, ShouldBeNil)
+			})
+		})
+
+		Convey("When Identity API v2 URL is provided", func() {
+			provider, serr := AuthenticateWithApplicationCredential(th.Endpoint()+"v2.0/", "app-id", "", "secret", "", "", "")
+
+			Convey("Then error is reported", func() {
+				So(serr, ShouldNotBeNil)
+				So(provider, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestNewIdentityClient() {
+	Convey("Given identity client is requested", s.T(), func() {
+
+		Convey("When authentication with unversioned URL is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+
+			Convey("and NewIdentityClient called", func() {
+				identityClient, serr := NewIdentityClient(provider)
+
+				Convey("Then client for Identity API v2 is returned", func() {
+					So(identityClient.Endpoint, ShouldEqual, th.Endpoint()+"v2.0/")
+					So(isIdentityV3(identityClient), ShouldBeFalse)
+				})
+
+				Convey("and no error reported", func() {
+					So(serr, ShouldBeNil)
+				})
+			})
+		})
+
+		Convey("When authentication with Identity API v3 URL is required", func() {
+			provider, serr := Authenticate(th.Endpoint()+"v3/", "me", "secret", "admin", "Default", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+
+			Convey("and NewIdentityClient called", func() {
+				identityClient, serr := NewIdentityClient(provider)
+
+				Convey("Then client for Identity API v3 is returned", func() {
+					So(identityClient.Endpoint, ShouldEqual, th.Endpoint()+"v3/")
+					So(isIdentityV3(identityClient), ShouldBeTrue)
+				})
+
+				Convey("and no error reported", func() {
+					So(serr, ShouldBeNil)
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetAllProjects() {
+	Convey("Given list of OpenStack projects is requested", s.T(), func() {
+
+		Convey("When authentication with Identity API v3 is required", func() {
+			provider, serr := Authenticate(th.Endpoint()+"v3/", "me", "secret", "admin", "Default", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+
+			identityClient, serr := NewIdentityClient(provider)
+			th.AssertNoErr(s.T(), serr)
+
+			Convey("and GetAllTenants called", func() {
+
+				tenantList, serr := GetAllTenants(identityClient)
+
+				Convey("Then list of projects without domains is returned", func() {
+					So(len(tenantList), ShouldEqual, 3)
+					So(tenantList[0], ShouldResemble, types.Tenant{Name: "demo", ID: "111111", DomainID: "default", ParentID: "default"})
+					So(tenantList[1], ShouldResemble, types.Tenant{Name: "admin", ID: "222222", DomainID: "default", ParentID: "default"})
+					So(tenantList[2], ShouldResemble, types.Tenant{Name: "dev", ID: "444444", DomainID: "default", ParentID: "111111"})
+				})
+
+				Convey("and no error reported", func() {
+					So(serr, ShouldBeNil)
+				})
+			})
+		})
+	})
+}
+
 func (s *TestSuite) TestGetNetworkCountPerTenant() {
 	Convey("Number of OpenStack networks per tenant is requested", s.T(), func() {
 
@@ -102,28 +247,847 @@ func (s *TestSuite) TestGetNetworkCountPerTenant() {
 			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
 			So(err, ShouldBeNil)
 
-			Convey("and GetNetworkCountPerTenant called", func() {
+			Convey("and GetNetworkCountPerTenant called", func() {
+
+				networkList, serr := GetNetworkCountPerTenant(networkClient, tenantList)
+
+				Convey("Then number of networks is returned", func() {
+					So(len(networkList), ShouldEqual, 2)
+					So(networkList["222222"], ShouldEqual, 2)
+					So(networkList["111111"], ShouldEqual, 1)
+					So(networkList["333333"], ShouldEqual, 0)
+				})
+
+				Convey("and no error reported", func() {
+					So(serr, ShouldBeNil)
+				})
+
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetNetworkTotalPerTenant() {
+	Convey("Number of OpenStack networks per tenant is requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetNetworkDetailsPerTenant called", func() {
+
+				counts, serr := GetNetworkDetailsPerTenant(networkClient, tenantList, nil)
+				networkList := counts.Total
+
+				Convey("Then number of networks is returned", func() {
+					So(len(networkList), ShouldEqual, 2)
+					So(networkList["222222"], ShouldEqual, 2)
+					So(networkList["111111"], ShouldEqual, 1)
+					So(networkList["333333"], ShouldEqual, 0)
+				})
+
+				Convey("and no error reported", func() {
+					So(serr, ShouldBeNil)
+				})
+
+			})
+
+			Convey("and GetNetworkDetailsPerTenant called for projects with the same name in different domains", func() {
+				projectList := []types.Tenant{
+					types.Tenant{ID: "222222", Name: "dev", DomainID: "default"},
+					types.Tenant{ID: "111111", Name: "dev", DomainID: "other"},
+				}
+				counts, serr := GetNetworkDetailsPerTenant(networkClient, projectList, nil)
+				networkList := counts.Total
+
+				Convey("Then numbers of networks are not merged", func() {
+					So(serr, ShouldBeNil)
+					So(len(networkList), ShouldEqual, 2)
+					So(networkList["222222"], ShouldEqual, 2)
+					So(networkList["111111"], ShouldEqual, 1)
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetNetworkAndRouterDetailsPerTenant() {
+	Convey("Details of OpenStack networks, subnets, routers and ports are requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetNetworkDetailsPerTenant called", func() {
+				networks, serr := GetNetworkDetailsPerTenant(networkClient, tenantList, nil)
+
+				Convey("Then number of networks per tenant and attributes of networks are returned", func() {
+					So(serr, ShouldBeNil)
+					So(networks.Total["222222"], ShouldEqual, 2)
+					So(networks.Total["111111"], ShouldEqual, 1)
+					So(len(networks.Networks), ShouldEqual, 2)
+					So(networks.Networks["28dd974d-0ec0-43cc-86ac-06773acb126f"].Name, ShouldEqual, "private")
+					So(networks.Networks["f3722668-e9e7-41dd-8086-5e1b9f5d8209"].External, ShouldBeTrue)
+					So(networks.Networks["f3722668-e9e7-41dd-8086-5e1b9f5d8209"].Shared, ShouldBeFalse)
+					So(networks.QoSPolicy["222222"], ShouldEqual, 1)
+					So(networks.QoSPolicy["111111"], ShouldEqual, 0)
+				})
+			})
+
+			Convey("and GetSubnetDetailsPerTenant called", func() {
+				subnets, serr := GetSubnetDetailsPerTenant(networkClient, tenantList, nil)
+
+				Convey("Then number of subnets per network is returned", func() {
+					So(serr, ShouldBeNil)
+					So(subnets.Total["222222"], ShouldEqual, 3)
+					So(subnets.PerNetwork["f3722668-e9e7-41dd-8086-5e1b9f5d8209"], ShouldEqual, 2)
+					So(subnets.PerNetwork["28dd974d-0ec0-43cc-86ac-06773acb126f"], ShouldEqual, 1)
+				})
+			})
+
+			Convey("and GetRouterDetailsPerTenant and GetPortsBreakdownPerTenant called", func() {
+				routers, serr := GetRouterDetailsPerTenant(networkClient, tenantList, nil)
+				So(serr, ShouldBeNil)
+				ports, serr := GetPortsBreakdownPerTenant(networkClient, tenantList, nil)
+				So(serr, ShouldBeNil)
+
+				Convey("Then routers and numbers of their interfaces are returned", func() {
+					So(routers.Total["222222"], ShouldEqual, 4)
+					So(len(routers.Routers), ShouldEqual, 1)
+					So(routers.Routers["a75c645a-6dcc-418c-9371-9be7054c395e"].Name, ShouldEqual, "router1")
+					So(ports.RouterInterfaces["a75c645a-6dcc-418c-9371-9be7054c395e"], ShouldEqual, 1)
+					So(ports.PerNetwork["28dd974d-0ec0-43cc-86ac-06773acb126f"], ShouldEqual, 3)
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetSubnetsCountPerTenant() {
+	Convey("Number of OpenStack subnets per tenant is requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetSubnetsCountPerTenant called", func() {
+
+				subnetList, serr := GetSubnetsCountPerTenant(networkClient, tenantList)
+
+				Convey("Then number of subnets is returned", func() {
+					So(len(subnetList), ShouldEqual, 2)
+					So(subnetList["222222"], ShouldEqual, 3)
+					So(subnetList["111111"], ShouldEqual, 0)
+					So(subnetList["333333"], ShouldEqual, 0)
+				})
+
+				Convey("and no error reported", func() {
+					So(serr, ShouldBeNil)
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetSubnetsTotalPerTenant() {
+	Convey("Number of OpenStack subnets per tenant is requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetSubnetDetailsPerTenant called", func() {
+
+				counts, serr := GetSubnetDetailsPerTenant(networkClient, tenantList, nil)
+				subnetList := counts.Total
+
+				Convey("Then number of subnets is returned", func() {
+					So(len(subnetList), ShouldEqual, 2)
+					So(subnetList["222222"], ShouldEqual, 3)
+					So(subnetList["111111"], ShouldEqual, 0)
+					So(subnetList["333333"], ShouldEqual, 0)
+				})
+
+				Convey("and no error reported", func() {
+					So(serr, ShouldBeNil)
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetRoutersCountPerTenant() {
+	Convey("Number of OpenStack routers per tenant is requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetRoutersCountPerTenant called", func() {
+
+				routerList, serr := GetRoutersCountPerTenant(networkClient, tenantList)
+
+				Convey("Then number of routers is returned", func() {
+					So(len(routerList), ShouldEqual, 2)
+					So(routerList["222222"], ShouldEqual, 4)
+					So(routerList["111111"], ShouldEqual, 0)
+					So(routerList["333333"], ShouldEqual, 0)
+				})
+
+				Convey("and no error reported", func() {
+					So(serr, ShouldBeNil)
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetRoutersTotalPerTenant() {
+	Convey("Number of OpenStack routers per tenant is requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetRouterDetailsPerTenant called", func() {
+
+				counts, serr := GetRouterDetailsPerTenant(networkClient, tenantList, nil)
+				routerList := counts.Total
+
+				Convey("Then number of routers is returned", func() {
+					So(len(routerList), ShouldEqual, 2)
+					So(routerList["222222"], ShouldEqual, 4)
+					So(routerList["111111"], ShouldEqual, 0)
+					So(routerList["333333"], ShouldEqual, 0)
+				})
+
+				Convey("and no error reported", func() {
+					So(serr, ShouldBeNil)
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetPortsCountPerTenant() {
+	Convey("Number of OpenStack ports per tenant is requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetPortsCountPerTenant called", func() {
+
+				portList, serr := GetPortsCountPerTenant(networkClient, tenantList)
+
+				Convey("Then number of tenants is returned", func() {
+					So(len(portList), ShouldEqual, 2)
+					So(portList["222222"], ShouldEqual, 3)
+					So(portList["111111"], ShouldEqual, 0)
+					So(portList["333333"], ShouldEqual, 0)
+				})
+
+				Convey("and no error reported", func() {
+					So(serr, ShouldBeNil)
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetPortsBreakdownPerTenantPaged() {
+	Convey("Number of OpenStack ports per tenant is requested page by page", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetPortsBreakdownPerTenant called with limit and tenant ID fields", func() {
+				opts := &tenantresources.ListOpts{Limit: 2, Fields: tenantresources.TenantIDFields}
+				counts, serr := GetPortsBreakdownPerTenant(networkClient, tenantList, opts)
+
+				Convey("Then ports from all pages are counted", func() {
+					So(len(counts.Total), ShouldEqual, 2)
+					So(counts.Total["222222"], ShouldEqual, 2)
+					So(counts.Total["111111"], ShouldEqual, 1)
+					So(counts.Status["ACTIVE"]["222222"], ShouldEqual, 2)
+					So(counts.Status["DOWN"]["111111"], ShouldEqual, 1)
+				})
+
+				Convey("and no error reported", func() {
+					So(serr, ShouldBeNil)
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetPortsBreakdownPerTenant() {
+	Convey("Number of OpenStack ports per tenant split by status and device owner is requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetPortsBreakdownPerTenant called", func() {
+
+				counts, serr := GetPortsBreakdownPerTenant(networkClient, tenantList, nil)
+
+				Convey("Then number of ports in each status is returned", func() {
+					So(serr, ShouldBeNil)
+					So(len(counts.Total), ShouldEqual, 2)
+					So(counts.Total["222222"], ShouldEqual, 3)
+					So(counts.Total["111111"], ShouldEqual, 0)
+					So(counts.Status["ACTIVE"]["222222"], ShouldEqual, 2)
+					So(counts.Status["DOWN"]["222222"], ShouldEqual, 1)
+					So(counts.Status["BUILD"]["222222"], ShouldEqual, 0)
+					So(counts.Status["ERROR"]["111111"], ShouldEqual, 0)
+				})
+
+				Convey("and number of ports of each class of device owner is returned", func() {
+					So(counts.Owner[PortOwnerCompute]["222222"], ShouldEqual, 1)
+					So(counts.Owner[PortOwnerDHCP]["222222"], ShouldEqual, 1)
+					So(counts.Owner[PortOwnerRouterInterface]["222222"], ShouldEqual, 1)
+					So(counts.Owner[PortOwnerUnbound]["222222"], ShouldEqual, 0)
+					So(counts.Owner[PortOwnerFloatingIP]["111111"], ShouldEqual, 0)
+				})
+
+				Convey("and number of ports with QoS policy attached is returned", func() {
+					So(counts.QoSPolicy["222222"], ShouldEqual, 1)
+					So(counts.QoSPolicy["111111"], ShouldEqual, 0)
+				})
+			})
+		})
+	})
+
+	Convey("Given device owners of ports", s.T(), func() {
+		Convey("Then they are classified", func() {
+			So(GetPortOwnerClass("compute:nova"), ShouldEqual, PortOwnerCompute)
+			So(GetPortOwnerClass("compute:az-1"), ShouldEqual, PortOwnerCompute)
+			So(GetPortOwnerClass("network:router_interface"), ShouldEqual, PortOwnerRouterInterface)
+			So(GetPortOwnerClass("network:router_interface_distributed"), ShouldEqual, PortOwnerRouterInterface)
+			So(GetPortOwnerClass("network:ha_router_replicated_interface"), ShouldEqual, PortOwnerRouterInterface)
+			So(GetPortOwnerClass("network:dhcp"), ShouldEqual, PortOwnerDHCP)
+			So(GetPortOwnerClass("network:floatingip"), ShouldEqual, PortOwnerFloatingIP)
+			So(GetPortOwnerClass(""), ShouldEqual, PortOwnerUnbound)
+			So(GetPortOwnerClass("network:router_gateway"), ShouldEqual, PortOwnerOther)
+		})
+	})
+}
+
+func (s *TestSuite) TestGetFloatingIPsTotalPerTenant() {
+	Convey("Number of OpenStack floating IPs per tenant is requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetFloatingIPsBreakdownPerTenant called", func() {
+
+				counts, serr := GetFloatingIPsBreakdownPerTenant(networkClient, tenantList, nil)
+				floatingipList := counts.Total
+
+				Convey("Then number of floating IPs for tenants is returned", func() {
+					So(len(floatingipList), ShouldEqual, 3)
+					So(floatingipList["222222"], ShouldEqual, 2)
+					So(floatingipList["111111"], ShouldEqual, 0)
+					So(floatingipList["333333"], ShouldEqual, 0)
+				})
+
+				Convey("and floating IPs of unknown tenants are counted too", func() {
+					So(floatingipList["999999"], ShouldEqual, 1)
+				})
+				Convey("and no error reported", func() {
+					So(serr, ShouldBeNil)
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetFloatingIPsCountPerTenant() {
+	Convey("Number of OpenStack floating IPs per tenant is requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetFloatingIPsCountPerTenant called", func() {
+
+				floatingipList, serr := GetFloatingIPsCountPerTenant(networkClient, tenantList)
+
+				Convey("Then number of floating IPs for tenants is returned", func() {
+					So(len(floatingipList), ShouldEqual, 2)
+					So(floatingipList["222222"], ShouldEqual, 2)
+					So(floatingipList["111111"], ShouldEqual, 0)
+					So(floatingipList["333333"], ShouldEqual, 0)
+				})
+				Convey("and no error reported", func() {
+					So(serr, ShouldBeNil)
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetFloatingIPsBreakdownPerTenant() {
+	Convey("Number of OpenStack floating IPs per tenant split by association and status is requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetFloatingIPsBreakdownPerTenant called with tenant ID fields", func() {
+
+				counts, serr := GetFloatingIPsBreakdownPerTenant(networkClient, tenantList, &tenantresources.ListOpts{Fields: tenantresources.TenantIDFields})
+
+				Convey("Then number of associated and unassociated floating IPs for tenants is returned", func() {
+					So(serr, ShouldBeNil)
+					So(counts.Total["222222"], ShouldEqual, 2)
+					So(counts.Associated["222222"], ShouldEqual, 1)
+					So(counts.Unassociated["222222"], ShouldEqual, 1)
+					So(counts.Associated["111111"], ShouldEqual, 0)
+					So(counts.Unassociated["111111"], ShouldEqual, 0)
+					So(counts.Unassociated["999999"], ShouldEqual, 1)
+				})
+
+				Convey("and number of floating IPs in each status is returned", func() {
+					So(counts.Status["ACTIVE"]["222222"], ShouldEqual, 1)
+					So(counts.Status["DOWN"]["222222"], ShouldEqual, 1)
+					So(counts.Status["DOWN"]["999999"], ShouldEqual, 1)
+					So(len(counts.Status["ERROR"]), ShouldEqual, len(tenantList))
+					So(counts.Status["ERROR"]["111111"], ShouldEqual, 0)
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetFloatingIPsUnlistedPerTenant() {
+	Convey("OpenStack floating IPs of unknown tenants are requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetFloatingIPsBreakdownPerTenant called", func() {
+
+				counts, serr := GetFloatingIPsBreakdownPerTenant(networkClient, tenantList, nil)
+
+				Convey("Then only floating IPs of tenants which do not exist are kept as unlisted", func() {
+					So(serr, ShouldBeNil)
+					unlisted := counts.Unlisted.Counted("Total", "")
+					So(len(unlisted), ShouldEqual, 1)
+					So(unlisted[0].ID, ShouldEqual, "61cea855-49cb-4846-997d-801b70c71bdd")
+					So(unlisted[0].TenantID, ShouldEqual, "999999")
+				})
+
+				Convey("and they are kept for each counter which counted them", func() {
+					So(counts.Unlisted.Counted("Unassociated", ""), ShouldHaveLength, 1)
+					So(counts.Unlisted.Counted("Status", "DOWN"), ShouldHaveLength, 1)
+					So(counts.Unlisted.Counted("Associated", ""), ShouldBeEmpty)
+					So(counts.Unlisted.Counted("Status", "ACTIVE"), ShouldBeEmpty)
+				})
+			})
+
+			Convey("and GetFloatingIPsBreakdownPerTenant called with empty list of tenants", func() {
+
+				counts, serr := GetFloatingIPsBreakdownPerTenant(networkClient, []types.Tenant{}, nil)
+
+				Convey("Then all floating IPs are kept as unlisted", func() {
+					So(serr, ShouldBeNil)
+					So(len(counts.Unlisted.Counted("Total", "")), ShouldEqual, 3)
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetAllocatableIPs() {
+	Convey("Given subnets with and without allocation pools", s.T(), func() {
+		withPools := tenantresources.Subnet{
+			CIDR: "10.0.0.0/24",
+			AllocationPools: []tenantresources.AllocationPool{
+				{Start: "10.0.0.2", End: "10.0.0.100"},
+				{Start: "10.0.0.200", End: "10.0.0.254"},
+			},
+		}
+		ipv6Pool := tenantresources.Subnet{
+			CIDR:            "2001:db8::/120",
+			AllocationPools: []tenantresources.AllocationPool{{Start: "2001:db8::2", End: "2001:db8::ff"}},
+		}
+
+		Convey("Then addresses of allocation pools are counted", func() {
+			So(getAllocatableIPs(withPools), ShouldEqual, 154)
+			So(getAllocatableIPs(ipv6Pool), ShouldEqual, 254)
+		})
+
+		Convey("Then usable addresses of CIDR are counted if there are no pools", func() {
+			So(getAllocatableIPs(tenantresources.Subnet{CIDR: "192.168.0.0/28"}), ShouldEqual, 14)
+			So(getAllocatableIPs(tenantresources.Subnet{CIDR: "2001:db8::/64"}), ShouldEqual, math.Pow(2, 64)-1)
+			So(getAllocatableIPs(tenantresources.Subnet{CIDR: "invalid"}), ShouldEqual, 0)
+		})
+	})
+}
+
+func (s *TestSuite) TestCalculateNetworkIPAvailabilities() {
+	Convey("Given networks, subnets and ports retrieved by counting passes", s.T(), func() {
+		networks := NetworkCounts{Networks: map[string]tenantresources.Network{
+			"net-b": {ID: "net-b", Name: "private", TenantID: "111111"},
+			"net-a": {ID: "net-a", Name: "empty", TenantID: "222222"},
+		}}
+		subnets := SubnetCounts{Subnets: map[string]tenantresources.Subnet{
+			"subnet-2": {ID: "subnet-2", Name: "private-v6", NetworkID: "net-b", CIDR: "2001:db8::/120", IPVersion: 6},
+			"subnet-1": {
+				ID:              "subnet-1",
+				Name:            "private-v4",
+				NetworkID:       "net-b",
+				CIDR:            "10.0.0.0/24",
+				IPVersion:       4,
+				AllocationPools: []tenantresources.AllocationPool{{Start: "10.0.0.2", End: "10.0.0.101"}},
+			},
+			"subnet-3": {ID: "subnet-3", NetworkID: "unknown", CIDR: "10.1.0.0/24", IPVersion: 4},
+		}}
+		ports := PortCounts{PerSubnet: map[string]int64{"subnet-1": 3, "subnet-2": 1, "subnet-3": 5}}
+
+		Convey("When CalculateNetworkIPAvailabilities is called", func() {
+			availabilities := CalculateNetworkIPAvailabilities(networks, subnets, ports)
+
+			Convey("Then networks and their subnets are returned ordered by ID", func() {
+				So(availabilities, ShouldHaveLength, 2)
+				So(availabilities[0].NetworkID, ShouldEqual, "net-a")
+				So(availabilities[0].Subnets, ShouldBeEmpty)
+				So(availabilities[1].NetworkID, ShouldEqual, "net-b")
+				So(availabilities[1].NetworkName, ShouldEqual, "private")
+				So(availabilities[1].TenantID, ShouldEqual, "111111")
+				So(availabilities[1].Subnets, ShouldHaveLength, 2)
+				So(availabilities[1].Subnets[0].SubnetID, ShouldEqual, "subnet-1")
+				So(availabilities[1].Subnets[1].SubnetID, ShouldEqual, "subnet-2")
+			})
+
+			Convey("Then allocatable addresses of subnets and fixed IPs of ports are summed per network", func() {
+				So(availabilities[1].Subnets[0].TotalIPs, ShouldEqual, 100)
+				So(availabilities[1].Subnets[0].UsedIPs, ShouldEqual, 3)
+				So(availabilities[1].Subnets[1].TotalIPs, ShouldEqual, 255)
+				So(availabilities[1].TotalIPs, ShouldEqual, 355)
+				So(availabilities[1].UsedIPs, ShouldEqual, 4)
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetAgents() {
+	Convey("OpenStack Neutron agents are requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetAgents called", func() {
+
+				agentList, serr := GetAgents(networkClient)
+
+				Convey("Then all agents are returned", func() {
+					So(serr, ShouldBeNil)
+					So(len(agentList), ShouldEqual, 2)
+					So(agentList[0].AgentType, ShouldEqual, "L3 agent")
+					So(agentList[0].Host, ShouldEqual, "network-1")
+					So(agentList[0].Alive, ShouldBeTrue)
+					So(agentList[1].AdminStateUp, ShouldBeFalse)
+				})
+
+				Convey("Then heartbeat timestamps are parsed as UTC", func() {
+					heartbeat, err := agentList[0].Heartbeat()
+					So(err, ShouldBeNil)
+					So(heartbeat, ShouldResemble, time.Date(2016, 9, 5, 11, 12, 13, 0, time.UTC))
+
+					heartbeat, err = agentList[1].Heartbeat()
+					So(err, ShouldBeNil)
+					So(heartbeat, ShouldResemble, time.Date(2016, 9, 5, 11, 12, 13, 500000000, time.UTC))
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetLoadBalancersBreakdownPerTenant() {
+	Convey("Number of OpenStack load balancing resources per tenant split by status is requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			Convey("and Octavia endpoint is not present in service catalog", func() {
+				So(NewOctaviaClient(provider), ShouldBeNil)
+				client, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+				So(err, ShouldBeNil)
+
+				Convey("and GetLoadBalancersBreakdownPerTenant called", func() {
+
+					counts, poolErrors, serr := GetLoadBalancersBreakdownPerTenant(client, tenantList, &tenantresources.ListOpts{Fields: tenantresources.TenantIDFields}, 2)
+
+					Convey("Then number of resources in each status is returned", func() {
+						So(serr, ShouldBeNil)
+						So(counts["loadbalancers"].Total["222222"], ShouldEqual, 1)
+						So(counts["loadbalancers"].Total["111111"], ShouldEqual, 1)
+						So(counts["loadbalancers"].ProvisioningStatus["PENDING"]["111111"], ShouldEqual, 1)
+						So(counts["loadbalancers"].OperatingStatus["OFFLINE"]["111111"], ShouldEqual, 1)
+						So(counts["loadbalancers"].ProvisioningStatus["ACTIVE"]["222222"], ShouldEqual, 1)
+						So(counts["listeners"].Total["222222"], ShouldEqual, 0)
+						So(counts["pools"].Total["222222"], ShouldEqual, 1)
+						So(counts["healthmonitors"].OperatingStatus["NO_MONITOR"]["111111"], ShouldEqual, 0)
+					})
+
+					Convey("and members of pools are counted for their project", func() {
+						So(counts["members"].Total["222222"], ShouldEqual, 1)
+						So(counts["members"].ProvisioningStatus["ERROR"]["222222"], ShouldEqual, 1)
+						So(counts["members"].OperatingStatus["ERROR"]["222222"], ShouldEqual, 1)
+					})
+
+					Convey("and pool which members cannot be listed is skipped and its error returned", func() {
+						So(counts["pools"].Total["111111"], ShouldEqual, 1)
+						So(counts["members"].Total["111111"], ShouldEqual, 0)
+						So(len(poolErrors), ShouldEqual, 1)
+						So(poolErrors["b6c3bbba-5e2d-4ee3-9d3c-f1d5c1e4a9a8"], ShouldNotBeNil)
+						So(poolErrors["b6c3bbba-5e2d-4ee3-9d3c-f1d5c1e4a9a8"].Fields()["poolID"], ShouldEqual, "b6c3bbba-5e2d-4ee3-9d3c-f1d5c1e4a9a8")
+					})
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetLoadBalancerQuotasPerTenant() {
+	Convey("Quotas of OpenStack load balancing resources per tenant are requested from Octavia", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+			client := &gophercloud.ServiceClient{
+				ProviderClient: provider,
+				Endpoint:       th.Endpoint() + "octavia/",
+				ResourceBase:   th.Endpoint() + "octavia/v2/",
+			}
+
+			Convey("and GetLoadBalancerQuotasPerTenant called", func() {
+
+				quotas, tenantErrors := GetLoadBalancerQuotasPerTenant(client, tenantList, 2)
+
+				Convey("Then quotas are returned under names of Neutron quotas", func() {
+					So(quotas["222222"]["loadbalancer"], ShouldEqual, 5)
+					So(quotas["222222"]["healthmonitor"], ShouldEqual, -1)
+					So(quotas["222222"]["listener"], ShouldEqual, 10)
+				})
+
+				Convey("and error is returned for tenant whose quotas cannot be retrieved", func() {
+					So(tenantErrors, ShouldHaveLength, 1)
+					So(tenantErrors["111111"], ShouldNotBeNil)
+					So(quotas, ShouldNotContainKey, "111111")
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetIPsecSiteConnectionsBreakdownPerTenant() {
+	Convey("Number of OpenStack IPsec site connections per tenant split by status is requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetIPsecSiteConnectionsBreakdownPerTenant called", func() {
 
-				networkList, serr := GetNetworkCountPerTenant(networkClient, tenantList)
+				counts, serr := GetIPsecSiteConnectionsBreakdownPerTenant(networkClient, tenantList, &tenantresources.ListOpts{Fields: tenantresources.TenantIDFields})
 
-				Convey("Then number of networks is returned", func() {
-					So(len(networkList), ShouldEqual, 2)
-					So(networkList["admin"], ShouldEqual, 2)
-					So(networkList["demo"], ShouldEqual, 1)
-					So(networkList["test"], ShouldEqual, 0)
+				Convey("Then number of site connections in each status is returned", func() {
+					So(serr, ShouldBeNil)
+					So(counts.Total["222222"], ShouldEqual, 2)
+					So(counts.Total["111111"], ShouldEqual, 0)
+					So(counts.Status["ACTIVE"]["222222"], ShouldEqual, 1)
+					So(counts.Status["DOWN"]["222222"], ShouldEqual, 1)
+					So(counts.Status["ERROR"]["222222"], ShouldEqual, 0)
+					So(counts.Status["ERROR"]["111111"], ShouldEqual, 0)
 				})
+			})
+		})
+	})
+}
 
-				Convey("and no error reported", func() {
+func (s *TestSuite) TestGetRBACPoliciesCountPerTenant() {
+	Convey("Number of OpenStack RBAC policies per tenant split by object type and action is requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetRBACPoliciesCountPerTenant called", func() {
+
+				counts, serr := GetRBACPoliciesCountPerTenant(networkClient, tenantList, &tenantresources.ListOpts{Fields: tenantresources.TenantIDFields})
+
+				Convey("Then number of RBAC policies of each object type and action is returned", func() {
 					So(serr, ShouldBeNil)
+					So(counts.Total["222222"], ShouldEqual, 3)
+					So(counts.Total["111111"], ShouldEqual, 1)
+					So(counts.ObjectType["network"]["222222"], ShouldEqual, 2)
+					So(counts.ObjectType["qos_policy"]["222222"], ShouldEqual, 1)
+					So(counts.ObjectType["subnetpool"]["111111"], ShouldEqual, 1)
+					So(counts.ObjectType["security_group"]["222222"], ShouldEqual, 0)
+					So(counts.Action["access_as_shared"]["222222"], ShouldEqual, 2)
+					So(counts.Action["access_as_external"]["222222"], ShouldEqual, 1)
+					So(counts.Action["access_as_shared"]["111111"], ShouldEqual, 1)
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetSubnetPoolsCountPerTenant() {
+	Convey("Address space of OpenStack subnet pools per tenant is requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+			identityClient := openstackgophercloud.NewIdentityV2(provider)
+			tenantList, _ := GetAllTenants(identityClient)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetSubnetPoolsCountPerTenant and GetSubnetDetailsPerTenant called", func() {
+
+				counts, serr := GetSubnetPoolsCountPerTenant(networkClient, tenantList, &tenantresources.ListOpts{})
+				So(serr, ShouldBeNil)
+				subnets, serr := GetSubnetDetailsPerTenant(networkClient, tenantList, &tenantresources.ListOpts{})
+				So(serr, ShouldBeNil)
+
+				Convey("Then subnet pools are counted per tenant", func() {
+					So(counts.Total["222222"], ShouldEqual, 1)
+					So(counts.Total["111111"], ShouldEqual, 1)
 				})
 
+				Convey("Then prefix capacity and space allocated to subnets are returned per pool", func() {
+					So(len(counts.Pools), ShouldEqual, 2)
+
+					pool := counts.Pools["f49a1319-423a-4ee6-ba54-1d95a4f6cc68"]
+					So(pool.Name, ShouldEqual, "pool-v4")
+					So(pool.TenantID, ShouldEqual, "222222")
+					So(pool.IPVersion, ShouldEqual, 4)
+					So(pool.Capacity, ShouldEqual, 65536)
+					So(subnets.PerSubnetPool["f49a1319-423a-4ee6-ba54-1d95a4f6cc68"], ShouldEqual, 256)
+
+					pool = counts.Pools["2fe7a1ae-6fa6-4b2b-8d2f-e8f5d0b1c9a3"]
+					So(pool.Shared, ShouldBeTrue)
+					So(pool.Capacity, ShouldEqual, 512)
+					So(subnets.PerSubnetPool["2fe7a1ae-6fa6-4b2b-8d2f-e8f5d0b1c9a3"], ShouldEqual, 0)
+				})
 			})
 		})
 	})
 }
 
-func (s *TestSuite) TestGetSubnetsCountPerTenant() {
-	Convey("Number of OpenStack subnets per tenant is requested", s.T(), func() {
+func (s *TestSuite) TestGetQoSPoliciesCountPerTenant() {
+	Convey("Number of OpenStack QoS policies and their rules per tenant is requested", s.T(), func() {
 
 		Convey("When authentication is required", func() {
 			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
@@ -135,27 +1099,59 @@ func (s *TestSuite) TestGetSubnetsCountPerTenant() {
 			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
 			So(err, ShouldBeNil)
 
-			Convey("and GetSubnetsCountPerTenant called", func() {
+			Convey("and GetQoSPoliciesCountPerTenant called", func() {
 
-				subnetList, serr := GetSubnetsCountPerTenant(networkClient, tenantList)
+				counts, serr := GetQoSPoliciesCountPerTenant(networkClient, tenantList, &tenantresources.ListOpts{Fields: tenantresources.TenantIDFields})
 
-				Convey("Then number of subnets is returned", func() {
-					So(len(subnetList), ShouldEqual, 2)
-					So(subnetList["admin"], ShouldEqual, 3)
-					So(subnetList["demo"], ShouldEqual, 0)
-					So(subnetList["test"], ShouldEqual, 0)
+				Convey("Then number of QoS policies and rules of each type is returned", func() {
+					So(serr, ShouldBeNil)
+					So(counts.Total["222222"], ShouldEqual, 2)
+					So(counts.Total["111111"], ShouldEqual, 0)
+					So(counts.Rules["222222"], ShouldEqual, 3)
+					So(counts.RuleType["bandwidth_limit"]["222222"], ShouldEqual, 2)
+					So(counts.RuleType["dscp_marking"]["222222"], ShouldEqual, 1)
+					So(counts.RuleType["minimum_bandwidth"]["222222"], ShouldEqual, 0)
+					So(counts.RuleType["minimum_bandwidth"]["111111"], ShouldEqual, 0)
 				})
+			})
+		})
+	})
+}
 
-				Convey("and no error reported", func() {
+func (s *TestSuite) TestIsExtensionAvailable() {
+	Convey("Availability of Neutron extensions is requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and IsExtensionAvailable called for loaded extension", func() {
+				available, serr := IsExtensionAvailable(networkClient, FWaaSExtensionAlias)
+
+				Convey("Then extension is reported as available", func() {
+					So(serr, ShouldBeNil)
+					So(available, ShouldBeTrue)
+				})
+			})
+
+			Convey("and IsExtensionAvailable called for extension which is not loaded", func() {
+				available, serr := IsExtensionAvailable(networkClient, "fwaas")
+
+				Convey("Then extension is reported as not available without error", func() {
 					So(serr, ShouldBeNil)
+					So(available, ShouldBeFalse)
 				})
 			})
 		})
 	})
 }
 
-func (s *TestSuite) TestGetRoutersCountPerTenant() {
-	Convey("Number of OpenStack routers per tenant is requested", s.T(), func() {
+func (s *TestSuite) TestGetFirewallGroupsBreakdownPerTenant() {
+	Convey("Number of OpenStack firewall groups per tenant split by status is requested", s.T(), func() {
 
 		Convey("When authentication is required", func() {
 			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
@@ -167,27 +1163,32 @@ func (s *TestSuite) TestGetRoutersCountPerTenant() {
 			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
 			So(err, ShouldBeNil)
 
-			Convey("and GetRoutersCountPerTenant called", func() {
+			Convey("and GetFirewallGroupsBreakdownPerTenant called", func() {
 
-				routerList, serr := GetRoutersCountPerTenant(networkClient, tenantList)
+				counts, serr := GetFirewallGroupsBreakdownPerTenant(networkClient, tenantList, &tenantresources.ListOpts{Fields: tenantresources.TenantIDFields})
 
-				Convey("Then number of routers is returned", func() {
-					So(len(routerList), ShouldEqual, 2)
-					So(routerList["admin"], ShouldEqual, 4)
-					So(routerList["demo"], ShouldEqual, 0)
-					So(routerList["test"], ShouldEqual, 0)
+				Convey("Then number of firewall groups in each status is returned", func() {
+					So(serr, ShouldBeNil)
+					So(counts.Total["222222"], ShouldEqual, 3)
+					So(counts.Total["111111"], ShouldEqual, 0)
+					So(counts.Status["ACTIVE"]["222222"], ShouldEqual, 1)
+					So(counts.Status["DOWN"]["222222"], ShouldEqual, 1)
+					So(counts.Status["PENDING"]["222222"], ShouldEqual, 1)
+					So(counts.Status["INACTIVE"]["222222"], ShouldEqual, 0)
+					So(counts.Status["ERROR"]["111111"], ShouldEqual, 0)
 				})
 
-				Convey("and no error reported", func() {
-					So(serr, ShouldBeNil)
+				Convey("and number of ports bound to firewall groups is returned", func() {
+					So(counts.Ports["222222"], ShouldEqual, 3)
+					So(counts.Ports["111111"], ShouldEqual, 0)
 				})
 			})
 		})
 	})
 }
 
-func (s *TestSuite) TestGetPortsCountPerTenant() {
-	Convey("Number of OpenStack ports per tenant is requested", s.T(), func() {
+func (s *TestSuite) TestGetSecurityGroupsCountPerTenant() {
+	Convey("Number of OpenStack security groups per tenant is requested", s.T(), func() {
 
 		Convey("When authentication is required", func() {
 			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
@@ -199,15 +1200,15 @@ func (s *TestSuite) TestGetPortsCountPerTenant() {
 			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
 			So(err, ShouldBeNil)
 
-			Convey("and GetPortsCountPerTenant called", func() {
+			Convey("and GetSecurityGroupsCountPerTenant called", func() {
 
-				portList, serr := GetPortsCountPerTenant(networkClient, tenantList)
+				securityGroupList, serr := GetSecurityGroupsCountPerTenant(networkClient, tenantList, nil)
 
-				Convey("Then number of tenants is returned", func() {
-					So(len(portList), ShouldEqual, 2)
-					So(portList["admin"], ShouldEqual, 3)
-					So(portList["demo"], ShouldEqual, 0)
-					So(portList["test"], ShouldEqual, 0)
+				Convey("Then number of security groups for tenants is returned", func() {
+					So(len(securityGroupList.Total), ShouldEqual, 2)
+					So(securityGroupList.Total["222222"], ShouldEqual, 2)
+					So(securityGroupList.Total["111111"], ShouldEqual, 1)
+					So(securityGroupList.Total["333333"], ShouldEqual, 0)
 				})
 
 				Convey("and no error reported", func() {
@@ -218,8 +1219,8 @@ func (s *TestSuite) TestGetPortsCountPerTenant() {
 	})
 }
 
-func (s *TestSuite) TestGetFloatingIPsCountPerTenant() {
-	Convey("Number of OpenStack floating IPs per tenant is requested", s.T(), func() {
+func (s *TestSuite) TestGetSecurityGroupRulesCountPerTenant() {
+	Convey("Number of OpenStack security group rules per tenant is requested", s.T(), func() {
 
 		Convey("When authentication is required", func() {
 			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
@@ -231,16 +1232,17 @@ func (s *TestSuite) TestGetFloatingIPsCountPerTenant() {
 			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
 			So(err, ShouldBeNil)
 
-			Convey("and GetFloatingIPsCountPerTenant called", func() {
+			Convey("and GetSecurityGroupRulesCountPerTenant called", func() {
 
-				floatingipList, serr := GetFloatingIPsCountPerTenant(networkClient, tenantList)
+				securityGroupRuleList, serr := GetSecurityGroupRulesCountPerTenant(networkClient, tenantList, nil)
 
-				Convey("Then number of floating IPs for tenants is returned", func() {
-					So(len(floatingipList), ShouldEqual, 2)
-					So(floatingipList["admin"], ShouldEqual, 2)
-					So(floatingipList["demo"], ShouldEqual, 0)
-					So(floatingipList["test"], ShouldEqual, 0)
+				Convey("Then number of security group rules for tenants is returned", func() {
+					So(len(securityGroupRuleList.Total), ShouldEqual, 2)
+					So(securityGroupRuleList.Total["222222"], ShouldEqual, 3)
+					So(securityGroupRuleList.Total["111111"], ShouldEqual, 1)
+					So(securityGroupRuleList.Total["333333"], ShouldEqual, 0)
 				})
+
 				Convey("and no error reported", func() {
 					So(serr, ShouldBeNil)
 				})
@@ -264,30 +1266,49 @@ func (s *TestSuite) TestGetQuotasPerTenant() {
 
 			Convey("and GetQuotasPerTenant called", func() {
 
-				quotaList, serr := GetQuotasPerTenant(networkClient, tenantList)
+				quotaList, tenantErrors := GetQuotasPerTenant(networkClient, tenantList, 2)
 
 				Convey("Then list of quotas per tenants is returned", func() {
 					So(len(quotaList), ShouldEqual, 2)
-					So(len(quotaList["admin"]), ShouldEqual, 9)
-					So(len(quotaList["demo"]), ShouldEqual, 9)
-					So(len(quotaList["test"]), ShouldEqual, 0)
+					So(len(quotaList["222222"]), ShouldEqual, 9)
+					So(len(quotaList["111111"]), ShouldEqual, 9)
+					So(len(quotaList["333333"]), ShouldEqual, 0)
 				})
 
 				Convey("and no error reported", func() {
-					So(serr, ShouldBeNil)
+					So(tenantErrors, ShouldBeEmpty)
 				})
 			})
 
 			Convey("and GetQuotasPerTenant called for non-existing tenant", func() {
 				nonexistingTenantList := []types.Tenant{types.Tenant{ID: "333333", Name: "test"}}
-				quotaList, serr := GetQuotasPerTenant(networkClient, nonexistingTenantList)
+				quotaList, tenantErrors := GetQuotasPerTenant(networkClient, nonexistingTenantList, 2)
 
 				Convey("Then list of quotas per tenants is returned", func() {
 					So(len(quotaList), ShouldEqual, 0)
 				})
 
-				Convey("and no error reported", func() {
-					So(serr, ShouldNotBeNil)
+				Convey("and error reported for the tenant", func() {
+					So(len(tenantErrors), ShouldEqual, 1)
+					So(tenantErrors, ShouldContainKey, "333333")
+					So(tenantErrors["333333"].Fields()["tenantName"], ShouldEqual, "test")
+				})
+			})
+
+			Convey("and GetQuotasPerTenant called for existing and non-existing tenants", func() {
+				mixedTenantList := []types.Tenant{
+					types.Tenant{ID: "222222", Name: "admin"},
+					types.Tenant{ID: "333333", Name: "test"},
+					types.Tenant{ID: "111111", Name: "demo"},
+				}
+				quotaList, tenantErrors := GetQuotasPerTenant(networkClient, mixedTenantList, 1)
+
+				Convey("Then failed tenant is skipped and quotas of remaining tenants are returned", func() {
+					So(len(quotaList), ShouldEqual, 2)
+					So(len(quotaList["222222"]), ShouldEqual, 9)
+					So(len(quotaList["111111"]), ShouldEqual, 9)
+					So(len(tenantErrors), ShouldEqual, 1)
+					So(tenantErrors, ShouldContainKey, "333333")
 				})
 			})
 
@@ -333,6 +1354,176 @@ func (s *TestSuite) TestGetQuotasForTenant() {
 	})
 }
 
+func (s *TestSuite) TestGetQuotaDetailsPerTenant() {
+	Convey("Given list of OpenStack quota details per tenant is requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetQuotaDetailsPerTenant called for tenants with quota details available", func() {
+				tenantList := []types.Tenant{types.Tenant{ID: "222222", Name: "admin"}}
+				detailsList, tenantErrors := GetQuotaDetailsPerTenant(networkClient, tenantList, 2)
+
+				Convey("Then list of quota details per tenants is returned", func() {
+					So(len(detailsList), ShouldEqual, 1)
+					So(len(detailsList["222222"]), ShouldEqual, 9)
+					So(detailsList["222222"]["port"].Used, ShouldEqual, 3)
+					So(detailsList["222222"]["port"].Reserved, ShouldEqual, 1)
+				})
+
+				Convey("and quota limits can be retrieved from details", func() {
+					quotaList := GetQuotaLimits(detailsList)
+					So(len(quotaList["222222"]), ShouldEqual, 9)
+					So(quotaList["222222"]["port"], ShouldEqual, 50)
+				})
+
+				Convey("and no error reported", func() {
+					So(tenantErrors, ShouldBeEmpty)
+				})
+			})
+
+			Convey("and GetQuotaDetailsPerTenant called when quota details are not available for some tenant", func() {
+				tenantList := []types.Tenant{types.Tenant{ID: "222222", Name: "admin"}, types.Tenant{ID: "111111", Name: "demo"}}
+				detailsList, tenantErrors := GetQuotaDetailsPerTenant(networkClient, tenantList, 2)
+
+				Convey("Then no quota details are returned", func() {
+					So(detailsList, ShouldBeNil)
+				})
+
+				Convey("and no error reported", func() {
+					So(tenantErrors, ShouldBeEmpty)
+				})
+			})
+
+			Convey("and GetQuotaDetailsPerTenant called when quota details are not available for the first tenant", func() {
+				tenantList := []types.Tenant{types.Tenant{ID: "111111", Name: "demo"}, types.Tenant{ID: "222222", Name: "admin"}}
+				detailsList, tenantErrors := GetQuotaDetailsPerTenant(networkClient, tenantList, 2)
+
+				Convey("Then no quota details are returned", func() {
+					So(detailsList, ShouldBeNil)
+				})
+
+				Convey("and no error reported", func() {
+					So(tenantErrors, ShouldBeEmpty)
+				})
+			})
+
+			Convey("and GetQuotaDetailsPerTenant called for empty list of tenants", func() {
+				detailsList, tenantErrors := GetQuotaDetailsPerTenant(networkClient, []types.Tenant{}, 2)
+
+				Convey("Then empty list of quota details is returned", func() {
+					So(detailsList, ShouldNotBeNil)
+					So(detailsList, ShouldBeEmpty)
+					So(tenantErrors, ShouldBeEmpty)
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestGetQuotaDetailsForTenant() {
+	Convey("Given OpenStack quota details for particular tenant are requested", s.T(), func() {
+
+		Convey("When authentication is required", func() {
+			provider, serr := Authenticate(th.Endpoint(), "me", "secret", "admin", "", "")
+			th.AssertNoErr(s.T(), serr)
+			th.CheckEquals(s.T(), s.Token, provider.TokenID)
+
+			networkClient, err := openstackgophercloud.NewNetworkV2(provider, gophercloud.EndpointOpts{})
+			So(err, ShouldBeNil)
+
+			Convey("and GetQuotaDetailsForTenant called", func() {
+				details, serr := GetQuotaDetailsForTenant(networkClient, "222222")
+
+				Convey("Then quota details are returned", func() {
+					So(len(details), ShouldEqual, 9)
+					So(details["network"].Limit, ShouldEqual, 13)
+					So(details["network"].Used, ShouldEqual, 2)
+				})
+
+				Convey("and no error reported", func() {
+					So(serr, ShouldBeNil)
+				})
+			})
+
+			Convey("and GetQuotaDetailsForTenant called when quota details extension is not available", func() {
+				details, serr := GetQuotaDetailsForTenant(networkClient, "111111")
+
+				Convey("Then no quota details are returned", func() {
+					So(details, ShouldBeNil)
+				})
+
+				Convey("and no error reported", func() {
+					So(serr, ShouldBeNil)
+				})
+			})
+
+			Convey("and GetQuotaDetailsForTenant called with incorrect response", func() {
+				details, serr := GetQuotaDetailsForTenant(networkClient, "333333")
+
+				Convey("Then no quota details are returned", func() {
+					So(len(details), ShouldEqual, 0)
+				})
+
+				Convey("and error reported", func() {
+					So(serr, ShouldNotBeNil)
+				})
+			})
+		})
+	})
+}
+
+func (s *TestSuite) TestForEachTenant() {
+	Convey("Given list of tenants processed by limited number of workers", s.T(), func() {
+		tenantList := []types.Tenant{}
+		for i := 0; i < 20; i++ {
+			tenantList = append(tenantList, types.Tenant{ID: fmt.Sprintf("%d", i), Name: fmt.Sprintf("tenant%d", i)})
+		}
+
+		var mutex sync.Mutex
+		inProgress, maxInProgress, processed := 0, 0, 0
+		tenantErrors := forEachTenant(tenantList, 3, func(tnt types.Tenant) serror.SnapError {
+			mutex.Lock()
+			inProgress++
+			processed++
+			if inProgress > maxInProgress {
+				maxInProgress = inProgress
+			}
+			mutex.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mutex.Lock()
+			inProgress--
+			mutex.Unlock()
+
+			if tnt.ID == "7" {
+				return serror.New(fmt.Errorf("tenant failed"))
+			}
+			return nil
+		})
+
+		Convey("Then all tenants are processed", func() {
+			So(processed, ShouldEqual, 20)
+		})
+
+		Convey("Then number of parallel calls does not exceed limit", func() {
+			So(maxInProgress, ShouldBeLessThanOrEqualTo, 3)
+		})
+
+		Convey("Then only failed tenant is reported", func() {
+			So(len(tenantErrors), ShouldEqual, 1)
+			So(tenantErrors, ShouldContainKey, "7")
+			So(tenantErrors["7"].Fields()["tenantName"], ShouldEqual, "tenant7")
+		})
+	})
+}
+
 func registerRoot() {
 	th.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, `
@@ -410,8 +1601,93 @@ func registerAuthentication(s *TestSuite) {
 	})
 }
 
-func registerTenants(s *TestSuite) {
-	th.Mux.HandleFunc("/v2.0/tenants", func(w http.ResponseWriter, r *http.Request) {
+func registerAuthenticationV3(s *TestSuite) {
+	th.Mux.HandleFunc("/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "POST")
+
+		var req struct {
+			Auth struct {
+				Identity struct {
+					ApplicationCredential *struct {
+						Secret string `json:"secret"`
+					} `json:"application_credential"`
+				} `json:"identity"`
+			} `json:"auth"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if cred := req.Auth.Identity.ApplicationCredential; cred != nil && cred.Secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Subject-Token", s.Token)
+		w.WriteHeader(http.StatusCreated)
+
+		fmt.Fprintf(w, `
+			{
+				"token": {
+					"expires_at": "2017-01-19T15:33:11.000000Z",
+					"issued_at": "2017-01-19T14:33:11.000000Z",
+					"methods": ["password"],
+					"project": {
+						"domain": {"id": "default", "name": "Default"},
+						"id": "222222",
+						"name": "admin"
+					},
+					"catalog": [
+						{
+							"endpoints": [
+								{
+									"id": "3ffe125aa59547029ed774c10b932349",
+									"interface": "public",
+									"region": "RegionOne",
+									"url": "%s"
+								}
+							],
+							"id": "efbf568dd1234f52a73869c8cab10d93",
+							"name": "neutron",
+							"type": "network"
+						}
+					]
+				}
+			}
+		`, s.NetworkServiceEndpoint)
+	})
+}
+
+func registerTenants(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/tenants", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"tenants": [
+					{
+						"description": "demo tenat",
+						"enabled": true,
+						"id": "111111",
+						"name": "demo"
+					},
+					{
+						"description": "admin tenant",
+						"enabled": true,
+						"id": "222222",
+						"name": "admin"
+					}
+				],
+				"tenants_links": []
+			}
+		`)
+	})
+}
+
+func registerProjects(s *TestSuite) {
+	th.Mux.HandleFunc("/v3/projects", func(w http.ResponseWriter, r *http.Request) {
 		th.TestMethod(s.T(), r, "GET")
 		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
 
@@ -420,23 +1696,51 @@ func registerTenants(s *TestSuite) {
 
 		fmt.Fprintf(w, `
 			{
-				"tenants": [
+				"links": {
+					"next": null,
+					"previous": null,
+					"self": "%s"
+				},
+				"projects": [
 					{
-						"description": "demo tenat",
+						"description": "demo project",
+						"domain_id": "default",
 						"enabled": true,
 						"id": "111111",
-						"name": "demo"
+						"is_domain": false,
+						"name": "demo",
+						"parent_id": "default"
 					},
 					{
-						"description": "admin tenant",
+						"description": "admin project",
+						"domain_id": "default",
 						"enabled": true,
 						"id": "222222",
-						"name": "admin"
+						"is_domain": false,
+						"name": "admin",
+						"parent_id": "default"
+					},
+					{
+						"description": "domain acting as project",
+						"domain_id": null,
+						"enabled": true,
+						"id": "default",
+						"is_domain": true,
+						"name": "Default",
+						"parent_id": null
+					},
+					{
+						"description": "development project",
+						"domain_id": "default",
+						"enabled": true,
+						"id": "444444",
+						"is_domain": false,
+						"name": "dev",
+						"parent_id": "111111"
 					}
-				],
-				"tenants_links": []
+				]
 			}
-		`)
+		`, th.Endpoint()+"v3/projects")
 	})
 }
 
@@ -498,6 +1802,7 @@ func registerNetworks(s *TestSuite) {
 			      "provider:network_type": "flat",
 			      "provider:physical_network": "public",
 			      "provider:segmentation_id": null,
+			      "qos_policy_id": "e8f1b9c6-2b6a-4d1e-9a43-2f9e0c1d5a77",
 			      "revision": 6,
 			      "router:external": true,
 			      "shared": false,
@@ -609,7 +1914,7 @@ func registerSubnets(s *TestSuite) {
 			      "network_id": "28dd974d-0ec0-43cc-86ac-06773acb126f",
 			      "revision": 2,
 			      "service_types": [],
-			      "subnetpool_id": null,
+			      "subnetpool_id": "f49a1319-423a-4ee6-ba54-1d95a4f6cc68",
 			      "tenant_id": "222222",
 			      "updated_at": "2016-09-08T12:01:36"
 			    },
@@ -794,6 +2099,40 @@ func registerPorts(s *TestSuite) {
 		w.Header().Add("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 
+		// paged listing limited to attributes needed to count ports
+		if r.URL.Query().Get("limit") != "" {
+			th.CheckDeepEquals(s.T(), tenantresources.PortFields, r.URL.Query()["fields"])
+			pageLink := fmt.Sprintf("%sv2.0/ports?fields=%s&limit=2&marker=0a3bdc80-5b3e-4fca-baca-9716d94f56b5", th.Endpoint(), strings.Join(tenantresources.PortFields, "&fields="))
+
+			if r.URL.Query().Get("marker") == "" {
+				fmt.Fprintf(w, `
+				{
+				  "ports": [
+				    {"id": "004e9c25-de09-4d4c-a2c3-50b05defaac9", "tenant_id": "222222", "status": "ACTIVE"},
+				    {"id": "0a3bdc80-5b3e-4fca-baca-9716d94f56b5", "tenant_id": "111111", "status": "DOWN"}
+				  ],
+				  "ports_links": [
+				    {"href": "%s", "rel": "next"}
+				  ]
+				}
+				`, pageLink)
+				return
+			}
+
+			th.CheckEquals(s.T(), "0a3bdc80-5b3e-4fca-baca-9716d94f56b5", r.URL.Query().Get("marker"))
+			fmt.Fprintf(w, `
+			{
+			  "ports": [
+			    {"id": "11bc164c-c2dd-4809-9b04-0ef4aaefd8a2", "tenant_id": "222222", "status": "ACTIVE"}
+			  ],
+			  "ports_links": [
+			    {"href": "%s", "rel": "previous"}
+			  ]
+			}
+			`, pageLink)
+			return
+		}
+
 		fmt.Fprintf(w, `
 			{
 			  "ports": [
@@ -828,6 +2167,7 @@ func registerPorts(s *TestSuite) {
 			      "name": "",
 			      "network_id": "28dd974d-0ec0-43cc-86ac-06773acb126f",
 			      "port_security_enabled": true,
+			      "qos_policy_id": "e8f1b9c6-2b6a-4d1e-9a43-2f9e0c1d5a77",
 			      "revision": 10,
 			      "security_groups": [
 				"f47fd611-39d9-4999-9b10-41b19e03d40a"
@@ -850,7 +2190,7 @@ func registerPorts(s *TestSuite) {
 			      "created_at": "2016-10-20T13:07:01",
 			      "description": "",
 			      "device_id": "92fcd563-3605-4cfa-9241-520566d79e68",
-			      "device_owner": "compute:nova",
+			      "device_owner": "network:dhcp",
 			      "extra_dhcp_opts": [],
 			      "fixed_ips": [
 				{
@@ -871,7 +2211,7 @@ func registerPorts(s *TestSuite) {
 			      "security_groups": [
 				"f47fd611-39d9-4999-9b10-41b19e03d40a"
 			      ],
-			      "status": "ACTIVE",
+			      "status": "DOWN",
 			      "tenant_id": "222222",
 			      "updated_at": "2016-10-20T13:07:23"
 			    },
@@ -918,6 +2258,9 @@ func registerFloatingIPs(s *TestSuite) {
 	th.Mux.HandleFunc("/v2.0/floatingips", func(w http.ResponseWriter, r *http.Request) {
 		th.TestMethod(s.T(), r, "GET")
 		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+		if fields := r.URL.Query()["fields"]; len(fields) > 0 {
+			th.CheckDeepEquals(s.T(), []string{"id", "tenant_id", "port_id", "fixed_ip_address", "status"}, fields)
+		}
 
 		w.Header().Add("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -932,7 +2275,7 @@ func registerFloatingIPs(s *TestSuite) {
 					"floating_ip_address": "192.0.0.4",
 					"tenant_id": "222222",
 					"status": "DOWN",
-					"port_id": "0a3bdc81-5b3e-4fca-baca-9716d94f56b5",
+					"port_id": null,
 					"id": "a75c645a-6dcd-418c-9371-9be7054c395e"
 				},
 				{
@@ -941,12 +2284,384 @@ func registerFloatingIPs(s *TestSuite) {
 					"fixed_ip_address": "192.0.0.2",
 					"floating_ip_address": "10.0.0.3",
 					"tenant_id": "222222",
-					"status": "DOWN",
+					"status": "ACTIVE",
 					"port_id": "004e9c25-de19-4d4c-a2c3-50b05defaac9",
 					"id": "bfdd31f8-ccda-4722-9319-13a7138e226c"
+				},
+				{
+					"floating_network_id": "28dc974d-0ec0-43cc-86ac-06773acb126f",
+					"router_id": null,
+					"fixed_ip_address": null,
+					"floating_ip_address": "192.0.0.9",
+					"tenant_id": "999999",
+					"status": "DOWN",
+					"port_id": null,
+					"id": "61cea855-49cb-4846-997d-801b70c71bdd"
+				}
+			]
+		}
+		`)
+	})
+}
+
+func registerAgents(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/agents", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"agents": [
+					{
+						"id": "04c62b91-b799-48b7-9cd5-2982db6df9c6",
+						"agent_type": "L3 agent",
+						"binary": "neutron-l3-agent",
+						"host": "network-1",
+						"availability_zone": "nova",
+						"alive": true,
+						"admin_state_up": true,
+						"heartbeat_timestamp": "2016-09-05 11:12:13"
+					},
+					{
+						"id": "840d5d68-5759-4e9e-812f-f3bd19214c7f",
+						"agent_type": "DHCP agent",
+						"binary": "neutron-dhcp-agent",
+						"host": "network-1",
+						"availability_zone": "nova",
+						"alive": false,
+						"admin_state_up": false,
+						"heartbeat_timestamp": "2016-09-05 11:12:13.500000"
+					}
+				]
+			}
+		`)
+	})
+}
+
+func registerLoadBalancerQuotas(s *TestSuite) {
+	th.Mux.HandleFunc("/octavia/v2/lbaas/quotas/222222", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"quota": {
+					"load_balancer": 5,
+					"listener": 10,
+					"pool": -1,
+					"member": -1,
+					"health_monitor": -1,
+					"l7policy": -1,
+					"l7rule": -1
+				}
+			}
+		`)
+	})
+}
+
+func registerLoadBalancers(s *TestSuite) {
+	statusFields := []string{"id", "tenant_id", "project_id", "provisioning_status", "operating_status"}
+	register := func(path string, expectedFields []string, body string) {
+		th.Mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			th.TestMethod(s.T(), r, "GET")
+			th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+			if fields := r.URL.Query()["fields"]; len(fields) > 0 {
+				th.CheckDeepEquals(s.T(), expectedFields, fields)
+			}
+
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+
+			fmt.Fprint(w, body)
+		})
+	}
+
+	register("/v2.0/lbaas/loadbalancers", statusFields, `
+		{
+			"loadbalancers": [
+				{
+					"id": "a36c20d0-18e9-42ce-88fd-82a35977ee8c",
+					"tenant_id": "222222",
+					"provisioning_status": "ACTIVE",
+					"operating_status": "ONLINE"
+				},
+				{
+					"id": "607226db-27ef-4d41-ae89-f2a800e9c2db",
+					"tenant_id": "111111",
+					"provisioning_status": "PENDING_CREATE",
+					"operating_status": "OFFLINE"
+				}
+			]
+		}
+	`)
+	register("/v2.0/lbaas/listeners", statusFields, `{"listeners": []}`)
+	register("/v2.0/lbaas/pools", append(statusFields, "members"), `
+		{
+			"pools": [
+				{
+					"id": "4c0a0a5f-cf8f-44b7-b912-957daa8ce5e5",
+					"tenant_id": "222222",
+					"provisioning_status": "ACTIVE",
+					"operating_status": "ERROR",
+					"members": [{"id": "957a1ace-1bd2-449b-8455-820b6e4b63f3"}]
+				},
+				{
+					"id": "b6c3bbba-5e2d-4ee3-9d3c-f1d5c1e4a9a8",
+					"tenant_id": "111111",
+					"provisioning_status": "ACTIVE",
+					"operating_status": "ONLINE",
+					"members": [{"id": "0d2a8b6c-8f5e-4a3f-9c61-3c4d1e7f2b90"}]
+				}
+			]
+		}
+	`)
+	register("/v2.0/lbaas/pools/4c0a0a5f-cf8f-44b7-b912-957daa8ce5e5/members", statusFields, `
+		{
+			"members": [
+				{
+					"id": "957a1ace-1bd2-449b-8455-820b6e4b63f3",
+					"project_id": "222222",
+					"provisioning_status": "ERROR",
+					"operating_status": "ERROR"
 				}
 			]
 		}
+	`)
+	register("/v2.0/lbaas/healthmonitors", statusFields, `{"healthmonitors": []}`)
+	th.Mux.HandleFunc("/v2.0/lbaas/pools/b6c3bbba-5e2d-4ee3-9d3c-f1d5c1e4a9a8/members", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+}
+
+func registerIPsecSiteConnections(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/vpn/ipsec-site-connections", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+		th.CheckDeepEquals(s.T(), []string{"id", "tenant_id", "status"}, r.URL.Query()["fields"])
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"ipsec_site_connections": [
+					{"id": "851f280f-5639-4ea3-81aa-e298525ab74b", "tenant_id": "222222", "status": "ACTIVE"},
+					{"id": "f7b1f5a5-2f85-4fb1-a1b8-10e8f1c4ca01", "tenant_id": "222222", "status": "DOWN"}
+				]
+			}
+		`)
+	})
+}
+
+func registerRBACPolicies(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/rbac-policies", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+		th.CheckDeepEquals(s.T(), []string{"id", "tenant_id", "object_type", "action"}, r.URL.Query()["fields"])
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"rbac_policies": [
+					{"id": "f8ecf7b3-4b3a-4f4a-b4b7-b1e5c3f1ad4e", "tenant_id": "222222", "object_type": "network", "action": "access_as_shared"},
+					{"id": "2cf7523a-93b5-4e69-9360-6c6bf986bb7c", "tenant_id": "222222", "object_type": "network", "action": "access_as_external"},
+					{"id": "7a1b1f4c-0f66-4d5d-8d4f-2c1f1a4f5a0e", "tenant_id": "222222", "object_type": "qos_policy", "action": "access_as_shared"},
+					{"id": "c9b9e5a4-6f4e-44ab-9a8c-33c4b3a1d7f2", "tenant_id": "111111", "object_type": "subnetpool", "action": "access_as_shared"}
+				]
+			}
+		`)
+	})
+}
+
+func registerSubnetPools(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/subnetpools", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"subnetpools": [
+					{"id": "f49a1319-423a-4ee6-ba54-1d95a4f6cc68", "tenant_id": "222222", "name": "pool-v4", "prefixes": ["10.0.0.0/16"], "ip_version": 4, "shared": false},
+					{"id": "2fe7a1ae-6fa6-4b2b-8d2f-e8f5d0b1c9a3", "tenant_id": "111111", "name": "pool-shared", "prefixes": ["192.168.0.0/24", "192.168.1.0/24"], "ip_version": 4, "shared": true}
+				]
+			}
+		`)
+	})
+}
+
+func registerQoSPolicies(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/qos/policies", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+		th.CheckDeepEquals(s.T(), []string{"id", "tenant_id", "rules"}, r.URL.Query()["fields"])
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"policies": [
+					{
+						"id": "e8f1b9c6-2b6a-4d1e-9a43-2f9e0c1d5a77",
+						"tenant_id": "222222",
+						"rules": [
+							{"id": "5f126d84-551a-4dcf-bb01-0e9c0df0c793", "type": "bandwidth_limit", "max_kbps": 10000, "direction": "egress"},
+							{"id": "a1b2c3d4-5e6f-4a7b-8c9d-0e1f2a3b4c5d", "type": "bandwidth_limit", "max_kbps": 10000, "direction": "ingress"},
+							{"id": "8a7c6f9e-3b1d-4e2f-a5c4-9d8e7f6a5b4c", "type": "dscp_marking", "dscp_mark": 26}
+						]
+					},
+					{"id": "3c1d5e7f-9a2b-4c6d-8e0f-1a2b3c4d5e6f", "tenant_id": "222222", "rules": []}
+				]
+			}
+		`)
+	})
+}
+
+func registerFWaaS(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/extensions/fwaas_v2", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"extension": {
+					"alias": "fwaas_v2",
+					"name": "Firewall service v2",
+					"description": "Extension for Firewall service v2",
+					"updated": "2016-08-16T00:00:00-00:00",
+					"links": []
+				}
+			}
+		`)
+	})
+
+	// FWaaS v1 extension is not loaded
+	th.Mux.HandleFunc("/v2.0/extensions/fwaas", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+
+		fmt.Fprintf(w, `{"NeutronError": {"type": "HTTPNotFound", "message": "Extension with alias fwaas does not exist", "detail": ""}}`)
+	})
+
+	th.Mux.HandleFunc("/v2.0/fwaas/firewall_groups", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+		th.CheckDeepEquals(s.T(), []string{"id", "tenant_id", "status", "ports"}, r.URL.Query()["fields"])
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"firewall_groups": [
+					{"id": "3b0ef8f4-82c7-44d4-a4fb-6177f9a21977", "tenant_id": "222222", "status": "ACTIVE", "ports": ["650bfd2f-7766-4a0d-839f-218f33e16998", "a3a8e8c1-9d1e-4f5b-8c6a-2b7d4e9f0a1b"]},
+					{"id": "a1b2c3d4-e5f6-4789-9abc-def012345678", "tenant_id": "222222", "status": "DOWN", "ports": []},
+					{"id": "0f1e2d3c-4b5a-4697-8877-665544332211", "tenant_id": "222222", "status": "PENDING_CREATE", "ports": ["5c6d7e8f-9a0b-4c1d-8e2f-3a4b5c6d7e8f"]}
+				]
+			}
+		`)
+	})
+}
+
+func registerSecurityGroups(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/security-groups", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"security_groups": [
+					{
+						"description": "default",
+						"id": "85cc3048-abc3-43cc-89b3-377341426ac5",
+						"name": "default",
+						"security_group_rules": [],
+						"tenant_id": "222222"
+					},
+					{
+						"description": "web servers",
+						"id": "5c5e7e53-0a0e-4d5b-b2d6-4b6e3a8b3e21",
+						"name": "web",
+						"security_group_rules": [],
+						"tenant_id": "222222"
+					},
+					{
+						"description": "default",
+						"id": "9d1c8d7c-3f5c-4f6b-8a3e-0c1d4f0a6b92",
+						"name": "default",
+						"security_group_rules": [],
+						"tenant_id": "111111"
+					}
+				]
+			}
+		`)
+	})
+}
+
+func registerSecurityGroupRules(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/security-group-rules", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"security_group_rules": [
+					{
+						"direction": "egress",
+						"ethertype": "IPv6",
+						"id": "3c0e45ff-adaf-4124-b083-bf390e5482ff",
+						"security_group_id": "85cc3048-abc3-43cc-89b3-377341426ac5",
+						"tenant_id": "222222"
+					},
+					{
+						"direction": "egress",
+						"ethertype": "IPv4",
+						"id": "93aa42e5-80db-4581-9391-3a608bd0e448",
+						"security_group_id": "85cc3048-abc3-43cc-89b3-377341426ac5",
+						"tenant_id": "222222"
+					},
+					{
+						"direction": "ingress",
+						"ethertype": "IPv4",
+						"id": "c0b09f00-1d49-4e64-a0a7-8a186d928138",
+						"port_range_max": 80,
+						"port_range_min": 80,
+						"protocol": "tcp",
+						"security_group_id": "5c5e7e53-0a0e-4d5b-b2d6-4b6e3a8b3e21",
+						"tenant_id": "222222"
+					},
+					{
+						"direction": "egress",
+						"ethertype": "IPv4",
+						"id": "f7d45c89-008e-4bab-88ad-d6811724c51c",
+						"security_group_id": "9d1c8d7c-3f5c-4f6b-8a3e-0c1d4f0a6b92",
+						"tenant_id": "111111"
+					}
+				]
+			}
 		`)
 	})
 }
@@ -999,3 +2714,46 @@ func registerQuotas(s *TestSuite) {
 		`)
 	})
 }
+
+func registerQuotaDetails(s *TestSuite) {
+	th.Mux.HandleFunc("/v2.0/quotas/222222/details", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, `
+			{
+				"quota": {
+					"subnet": {"limit": 10, "used": 3, "reserved": 0},
+					"network": {"limit": 13, "used": 2, "reserved": 0},
+					"floatingip": {"limit": 50, "used": 2, "reserved": 0},
+					"subnetpool": {"limit": -1, "used": 0, "reserved": 0},
+					"security_group_rule": {"limit": 100, "used": 3, "reserved": 0},
+					"security_group": {"limit": 10, "used": 2, "reserved": 0},
+					"router": {"limit": 15, "used": 4, "reserved": 0},
+					"rbac_policy": {"limit": -1, "used": 0, "reserved": 0},
+					"port": {"limit": 50, "used": 3, "reserved": 1}
+				}
+			}
+		`)
+	})
+	th.Mux.HandleFunc("/v2.0/quotas/111111/details", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(s.T(), r, "GET")
+		th.TestHeader(s.T(), r, "X-Auth-Token", s.Token)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+
+		fmt.Fprintf(w, `
+			{
+				"NeutronError": {
+					"type": "HTTPNotFound",
+					"message": "The resource could not be found.",
+					"detail": ""
+				}
+			}
+		`)
+	})
+}
@@ -0,0 +1,69 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package projects
+
+import (
+	"github.com/mitchellh/mapstructure"
+	"github.com/rackspace/gophercloud/pagination"
+)
+
+// Project represents OpenStack project returned by Identity API v3
+type Project struct {
+	ID       string `mapstructure:"id"`
+	Name     string `mapstructure:"name"`
+	DomainID string `mapstructure:"domain_id"`
+	ParentID string `mapstructure:"parent_id"`
+	Enabled  bool   `mapstructure:"enabled"`
+	IsDomain bool   `mapstructure:"is_domain"`
+}
+
+// ProjectPage is a single page of Project results.
+type ProjectPage struct {
+	pagination.LinkedPageBase
+}
+
+// IsEmpty determines whether or not a page of Projects contains any results.
+func (page ProjectPage) IsEmpty() (bool, error) {
+	projects, err := ExtractProjects(page)
+	if err != nil {
+		return false, err
+	}
+	return len(projects) == 0, nil
+}
+
+// NextPageURL extracts the "next" link from the links section of the result.
+func (page ProjectPage) NextPageURL() (string, error) {
+	var resp struct {
+		Links struct {
+			Next string `mapstructure:"next"`
+		} `mapstructure:"links"`
+	}
+	err := mapstructure.Decode(page.Body, &resp)
+	return resp.Links.Next, err
+}
+
+// ExtractProjects returns a slice of Projects contained in a single page of results.
+func ExtractProjects(page pagination.Page) ([]Project, error) {
+	var resp struct {
+		Projects []Project `mapstructure:"projects"`
+	}
+	err := mapstructure.Decode(page.(ProjectPage).Body, &resp)
+	return resp.Projects, err
+}
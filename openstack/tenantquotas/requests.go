@@ -25,7 +25,8 @@ import (
 )
 
 const (
-	quotasPath = "quotas"
+	quotasPath  = "quotas"
+	detailsPath = "details"
 )
 
 // Get will retrieve the volume type with the provided ID. To extract the volume
@@ -39,3 +40,15 @@ func Get(client *gophercloud.ServiceClient, tenant string) Result {
 	_, res.Err = client.Get(url, &res.Body, &reqOpts)
 	return res
 }
+
+// GetDetails will retrieve quota details (limit, used and reserved resources) for the provided tenant.
+// It requires quota details extension, to extract the details from the result, call the Extract method on the DetailsResult.
+func GetDetails(client *gophercloud.ServiceClient, tenant string) DetailsResult {
+	var res DetailsResult
+	reqOpts := gophercloud.RequestOpts{
+		OkCodes: []int{http.StatusOK},
+	}
+	url := client.ServiceURL(quotasPath, tenant, detailsPath)
+	_, res.Err = client.Get(url, &res.Body, &reqOpts)
+	return res
+}
@@ -35,3 +35,25 @@ func (r Result) Extract() (map[string]map[string]int64, error) {
 	err := mapstructure.Decode(r.Body, &resp)
 	return resp, err
 }
+
+//QuotaDetails represents limit, used and reserved amount of a single resource
+type QuotaDetails struct {
+	Limit    int64 `mapstructure:"limit"`
+	Used     int64 `mapstructure:"used"`
+	Reserved int64 `mapstructure:"reserved"`
+}
+
+//DetailsResult represents the result of a get details operation.
+type DetailsResult struct {
+	gophercloud.Result
+}
+
+// Extract will get the quota details out of the DetailsResult object.
+func (r DetailsResult) Extract() (map[string]map[string]QuotaDetails, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	var resp map[string]map[string]QuotaDetails
+	err := mapstructure.Decode(r.Body, &resp)
+	return resp, err
+}
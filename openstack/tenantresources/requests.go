@@ -0,0 +1,133 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantresources
+
+import (
+	"github.com/rackspace/gophercloud"
+	"github.com/rackspace/gophercloud/pagination"
+)
+
+// Resource describes collection of Networking API resources owned by tenants
+type Resource struct {
+	// Path is a URL path of collection
+	Path string
+	// Key is a name of collection in response body
+	Key string
+}
+
+var (
+	// Networks collection of networks
+	Networks = Resource{Path: "networks", Key: "networks"}
+	// Subnets collection of subnets
+	Subnets = Resource{Path: "subnets", Key: "subnets"}
+	// Routers collection of routers
+	Routers = Resource{Path: "routers", Key: "routers"}
+	// Ports collection of ports
+	Ports = Resource{Path: "ports", Key: "ports"}
+	// FloatingIPs collection of floating IPs
+	FloatingIPs = Resource{Path: "floatingips", Key: "floatingips"}
+	// SecurityGroups collection of security groups
+	SecurityGroups = Resource{Path: "security-groups", Key: "security_groups"}
+	// SecurityGroupRules collection of security group rules
+	SecurityGroupRules = Resource{Path: "security-group-rules", Key: "security_group_rules"}
+	// RBACPolicies collection of RBAC policies
+	RBACPolicies = Resource{Path: "rbac-policies", Key: "rbac_policies"}
+	// SubnetPools collection of subnet pools
+	SubnetPools = Resource{Path: "subnetpools", Key: "subnetpools"}
+	// QoSPolicies collection of QoS policies
+	QoSPolicies = Resource{Path: "qos/policies", Key: "policies"}
+	// FirewallGroups collection of FWaaS v2 firewall groups
+	FirewallGroups = Resource{Path: "fwaas/firewall_groups", Key: "firewall_groups"}
+	// FirewallPolicies collection of FWaaS v2 firewall policies
+	FirewallPolicies = Resource{Path: "fwaas/firewall_policies", Key: "firewall_policies"}
+	// FirewallRules collection of FWaaS v2 firewall rules
+	FirewallRules = Resource{Path: "fwaas/firewall_rules", Key: "firewall_rules"}
+	// IKEPolicies collection of VPN IKE policies
+	IKEPolicies = Resource{Path: "vpn/ikepolicies", Key: "ikepolicies"}
+	// IPsecPolicies collection of VPN IPsec policies
+	IPsecPolicies = Resource{Path: "vpn/ipsecpolicies", Key: "ipsecpolicies"}
+	// VPNServices collection of VPN services
+	VPNServices = Resource{Path: "vpn/vpnservices", Key: "vpnservices"}
+	// IPsecSiteConnections collection of IPsec site connections
+	IPsecSiteConnections = Resource{Path: "vpn/ipsec-site-connections", Key: "ipsec_site_connections"}
+)
+
+// TenantIDFields limits returned attributes to those needed to count resources per tenant,
+// ID is required by Neutron to build marker of the next page
+var TenantIDFields = []string{"id", "tenant_id"}
+
+// FloatingIPFields limits returned attributes of floating IPs to those needed to count them per tenant, association and status
+var FloatingIPFields = []string{"id", "tenant_id", "port_id", "fixed_ip_address", "status"}
+
+// PortFields limits returned attributes of ports to those needed to count them per tenant, status, device owner, network, router and QoS policy
+// and to count their addresses per subnet
+var PortFields = []string{"id", "tenant_id", "status", "device_owner", "device_id", "network_id", "qos_policy_id", "fixed_ips"}
+
+// NetworkFields limits returned attributes of networks to those needed to count them per tenant and QoS policy and to describe them
+var NetworkFields = []string{"id", "tenant_id", "name", "shared", "router:external", "qos_policy_id"}
+
+// SubnetFields limits returned attributes of subnets to those needed to count them per tenant and network, to calculate address space allocated from subnet pools
+// and to calculate their IP availability
+var SubnetFields = []string{"id", "tenant_id", "name", "network_id", "subnetpool_id", "cidr", "ip_version", "allocation_pools"}
+
+// RouterFields limits returned attributes of routers to those needed to count them per tenant and to describe them
+var RouterFields = []string{"id", "tenant_id", "name", "external_gateway_info"}
+
+// RBACPolicyFields limits returned attributes of RBAC policies to those needed to count them per tenant, object type and action
+var RBACPolicyFields = []string{"id", "tenant_id", "object_type", "action"}
+
+// SubnetPoolFields limits returned attributes of subnet pools to those needed to count them per tenant and to calculate their capacity
+var SubnetPoolFields = []string{"id", "tenant_id", "name", "prefixes", "ip_version", "shared"}
+
+// QoSPolicyFields limits returned attributes of QoS policies to those needed to count them and their rules per tenant
+var QoSPolicyFields = []string{"id", "tenant_id", "rules"}
+
+// FirewallGroupFields limits returned attributes of firewall groups to those needed to count them per tenant and status and to count their ports
+var FirewallGroupFields = []string{"id", "tenant_id", "status", "ports"}
+
+// IPsecSiteConnectionFields limits returned attributes of IPsec site connections to those needed to count them per tenant and status
+var IPsecSiteConnectionFields = []string{"id", "tenant_id", "status"}
+
+// ListOpts controls paging and attributes of resources returned by the List call.
+type ListOpts struct {
+	// Limit is a maximum number of resources returned in a single page, 0 means server default
+	Limit int `q:"limit"`
+	// Marker is ID of the last resource of previous page
+	Marker string `q:"marker"`
+	// Fields limits attributes of returned resources, all attributes are returned if empty
+	Fields []string `q:"fields"`
+}
+
+// List enumerates resources of given collection, pages are retrieved using links returned by Neutron.
+func List(client *gophercloud.ServiceClient, resource Resource, opts *ListOpts) pagination.Pager {
+	createPage := func(r pagination.PageResult) pagination.Page {
+		return ResourcePage{LinkedPageBase: pagination.LinkedPageBase{PageResult: r}, key: resource.Key}
+	}
+
+	url := client.ServiceURL(resource.Path)
+	if opts != nil {
+		q, err := gophercloud.BuildQueryString(opts)
+		if err != nil {
+			return pagination.Pager{Err: err}
+		}
+		url += q.String()
+	}
+	return pagination.NewPager(client, url, createPage)
+}
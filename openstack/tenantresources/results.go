@@ -0,0 +1,259 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantresources
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/rackspace/gophercloud"
+	"github.com/rackspace/gophercloud/pagination"
+)
+
+// TenantResource represents attributes common to all resources owned by tenants
+type TenantResource struct {
+	ID       string `mapstructure:"id"`
+	TenantID string `mapstructure:"tenant_id"`
+}
+
+// FloatingIP represents attributes of floating IP which indicate its association and status
+type FloatingIP struct {
+	ID       string `mapstructure:"id"`
+	TenantID string `mapstructure:"tenant_id"`
+	PortID   string `mapstructure:"port_id"`
+	FixedIP  string `mapstructure:"fixed_ip_address"`
+	Status   string `mapstructure:"status"`
+}
+
+// Port represents attributes of port which indicate its status, owner, network, QoS policy and allocated addresses
+type Port struct {
+	ID          string    `mapstructure:"id"`
+	TenantID    string    `mapstructure:"tenant_id"`
+	NetworkID   string    `mapstructure:"network_id"`
+	Status      string    `mapstructure:"status"`
+	DeviceOwner string    `mapstructure:"device_owner"`
+	DeviceID    string    `mapstructure:"device_id"`
+	QoSPolicyID string    `mapstructure:"qos_policy_id"`
+	FixedIPs    []FixedIP `mapstructure:"fixed_ips"`
+}
+
+// FixedIP represents IP address allocated to port from subnet
+type FixedIP struct {
+	SubnetID  string `mapstructure:"subnet_id"`
+	IPAddress string `mapstructure:"ip_address"`
+}
+
+// Network represents attributes of network which identify and describe it
+type Network struct {
+	ID          string `mapstructure:"id"`
+	Name        string `mapstructure:"name"`
+	TenantID    string `mapstructure:"tenant_id"`
+	Shared      bool   `mapstructure:"shared"`
+	External    bool   `mapstructure:"router:external"`
+	QoSPolicyID string `mapstructure:"qos_policy_id"`
+}
+
+// Router represents attributes of router which identify and describe it
+type Router struct {
+	ID                  string                 `mapstructure:"id"`
+	Name                string                 `mapstructure:"name"`
+	TenantID            string                 `mapstructure:"tenant_id"`
+	ExternalGatewayInfo map[string]interface{} `mapstructure:"external_gateway_info"`
+}
+
+// Subnet represents attributes of subnet which describe its address space
+type Subnet struct {
+	ID              string           `mapstructure:"id"`
+	Name            string           `mapstructure:"name"`
+	TenantID        string           `mapstructure:"tenant_id"`
+	NetworkID       string           `mapstructure:"network_id"`
+	SubnetPoolID    string           `mapstructure:"subnetpool_id"`
+	CIDR            string           `mapstructure:"cidr"`
+	IPVersion       int              `mapstructure:"ip_version"`
+	AllocationPools []AllocationPool `mapstructure:"allocation_pools"`
+}
+
+// AllocationPool represents range of addresses of subnet which can be allocated to ports
+type AllocationPool struct {
+	Start string `mapstructure:"start"`
+	End   string `mapstructure:"end"`
+}
+
+// RBACPolicy represents attributes of RBAC policy which indicate type of shared object and granted access
+type RBACPolicy struct {
+	ID         string `mapstructure:"id"`
+	TenantID   string `mapstructure:"tenant_id"`
+	ObjectType string `mapstructure:"object_type"`
+	Action     string `mapstructure:"action"`
+}
+
+// SubnetPool represents attributes of subnet pool which identify it and describe its address space
+type SubnetPool struct {
+	ID        string   `mapstructure:"id"`
+	Name      string   `mapstructure:"name"`
+	TenantID  string   `mapstructure:"tenant_id"`
+	Prefixes  []string `mapstructure:"prefixes"`
+	IPVersion int      `mapstructure:"ip_version"`
+	Shared    bool     `mapstructure:"shared"`
+}
+
+// QoSPolicy represents attributes of QoS policy which indicate its rules
+type QoSPolicy struct {
+	ID       string    `mapstructure:"id"`
+	TenantID string    `mapstructure:"tenant_id"`
+	Rules    []QoSRule `mapstructure:"rules"`
+}
+
+// QoSRule represents rule of QoS policy
+type QoSRule struct {
+	ID   string `mapstructure:"id"`
+	Type string `mapstructure:"type"`
+}
+
+// FirewallGroup represents attributes of firewall group which indicate its status and ports it is bound to
+type FirewallGroup struct {
+	ID       string   `mapstructure:"id"`
+	TenantID string   `mapstructure:"tenant_id"`
+	Status   string   `mapstructure:"status"`
+	Ports    []string `mapstructure:"ports"`
+}
+
+// IPsecSiteConnection represents attributes of IPsec site connection which indicate its status
+type IPsecSiteConnection struct {
+	ID       string `mapstructure:"id"`
+	TenantID string `mapstructure:"tenant_id"`
+	Status   string `mapstructure:"status"`
+}
+
+// ResourcePage is a single page of resources of one collection.
+type ResourcePage struct {
+	pagination.LinkedPageBase
+	key string
+}
+
+// IsEmpty determines whether or not a page contains any resources.
+func (page ResourcePage) IsEmpty() (bool, error) {
+	resources, err := ExtractTenantResources(page)
+	if err != nil {
+		return false, err
+	}
+	return len(resources) == 0, nil
+}
+
+// NextPageURL extracts the "next" link from the <collection>_links section of the result.
+func (page ResourcePage) NextPageURL() (string, error) {
+	body, ok := page.Body.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("Expected an object, but was %#v", page.Body)
+	}
+
+	var links []gophercloud.Link
+	if err := mapstructure.Decode(body[page.key+"_links"], &links); err != nil {
+		return "", err
+	}
+	return gophercloud.ExtractNextURL(links)
+}
+
+// ExtractTenantResources returns a slice of resources contained in a single page of results.
+func ExtractTenantResources(page pagination.Page) ([]TenantResource, error) {
+	var resources []TenantResource
+	err := decodePage(page, &resources)
+	return resources, err
+}
+
+// ExtractFloatingIPs returns a slice of floating IPs contained in a single page of results.
+func ExtractFloatingIPs(page pagination.Page) ([]FloatingIP, error) {
+	var floatingIPs []FloatingIP
+	err := decodePage(page, &floatingIPs)
+	return floatingIPs, err
+}
+
+// ExtractPorts returns a slice of ports contained in a single page of results.
+func ExtractPorts(page pagination.Page) ([]Port, error) {
+	var ports []Port
+	err := decodePage(page, &ports)
+	return ports, err
+}
+
+// ExtractNetworks returns a slice of networks contained in a single page of results.
+func ExtractNetworks(page pagination.Page) ([]Network, error) {
+	var networks []Network
+	err := decodePage(page, &networks)
+	return networks, err
+}
+
+// ExtractSubnets returns a slice of subnets contained in a single page of results.
+func ExtractSubnets(page pagination.Page) ([]Subnet, error) {
+	var subnets []Subnet
+	err := decodePage(page, &subnets)
+	return subnets, err
+}
+
+// ExtractRouters returns a slice of routers contained in a single page of results.
+func ExtractRouters(page pagination.Page) ([]Router, error) {
+	var routers []Router
+	err := decodePage(page, &routers)
+	return routers, err
+}
+
+// ExtractRBACPolicies returns a slice of RBAC policies contained in a single page of results.
+func ExtractRBACPolicies(page pagination.Page) ([]RBACPolicy, error) {
+	var policies []RBACPolicy
+	err := decodePage(page, &policies)
+	return policies, err
+}
+
+// ExtractSubnetPools returns a slice of subnet pools contained in a single page of results.
+func ExtractSubnetPools(page pagination.Page) ([]SubnetPool, error) {
+	var subnetPools []SubnetPool
+	err := decodePage(page, &subnetPools)
+	return subnetPools, err
+}
+
+// ExtractQoSPolicies returns a slice of QoS policies contained in a single page of results.
+func ExtractQoSPolicies(page pagination.Page) ([]QoSPolicy, error) {
+	var policies []QoSPolicy
+	err := decodePage(page, &policies)
+	return policies, err
+}
+
+// ExtractFirewallGroups returns a slice of firewall groups contained in a single page of results.
+func ExtractFirewallGroups(page pagination.Page) ([]FirewallGroup, error) {
+	var firewallGroups []FirewallGroup
+	err := decodePage(page, &firewallGroups)
+	return firewallGroups, err
+}
+
+// ExtractIPsecSiteConnections returns a slice of IPsec site connections contained in a single page of results.
+func ExtractIPsecSiteConnections(page pagination.Page) ([]IPsecSiteConnection, error) {
+	var connections []IPsecSiteConnection
+	err := decodePage(page, &connections)
+	return connections, err
+}
+
+// decodePage decodes resources contained in a single page of results into given slice.
+func decodePage(page pagination.Page, resources interface{}) error {
+	resourcePage := page.(ResourcePage)
+	body, ok := resourcePage.Body.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("Expected an object, but was %#v", resourcePage.Body)
+	}
+	return mapstructure.Decode(body[resourcePage.key], resources)
+}
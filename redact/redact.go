@@ -0,0 +1,79 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redact
+
+import (
+	"strings"
+
+	"github.com/intelsdi-x/snap/core/serror"
+)
+
+const (
+	// Mask replaces values of sensitive fields
+	Mask = "***"
+)
+
+// sensitiveKeys contains parts of field names which indicate that field holds credentials
+var sensitiveKeys = []string{
+	"password",
+	"secret",
+	"token",
+}
+
+// New creates SnapError with fields in which credentials (passwords, tokens, application credential secrets) are masked.
+// It should be used instead of serror.New for all errors emitted by plugin.
+func New(err error, fields ...map[string]interface{}) serror.SnapError {
+	if len(fields) == 0 {
+		return serror.New(err)
+	}
+	return serror.New(err, Fields(fields[0]))
+}
+
+// Fields returns copy of fields in which values of sensitive fields are masked, nested maps are processed recursively
+func Fields(fields map[string]interface{}) map[string]interface{} {
+	if fields == nil {
+		return nil
+	}
+
+	redacted := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if IsSensitive(k) {
+			redacted[k] = Mask
+			continue
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			redacted[k] = Fields(nested)
+			continue
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+// IsSensitive checks whether field with given name holds credentials
+func IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
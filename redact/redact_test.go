@@ -0,0 +1,88 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redact
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFields(t *testing.T) {
+	Convey("Given fields of SnapError with credentials", t, func() {
+		fields := map[string]interface{}{
+			"IdentityEndpoint":              "http://127.0.0.1:5000/v3/",
+			"Username":                      "admin",
+			"Password":                      "Sup3rS3cret",
+			"TokenID":                       "cefb1b0ba45744488e6ed702db699327",
+			"application_credential_secret": "AppS3cret",
+			"request": map[string]interface{}{
+				"X-Auth-Token": "cefb1b0ba45744488e6ed702db699327",
+				"tenantName":   "admin",
+			},
+		}
+
+		Convey("When fields are redacted", func() {
+			redacted := Fields(fields)
+
+			Convey("Then credentials are masked", func() {
+				So(redacted["Password"], ShouldEqual, Mask)
+				So(redacted["TokenID"], ShouldEqual, Mask)
+				So(redacted["application_credential_secret"], ShouldEqual, Mask)
+				So(redacted["request"].(map[string]interface{})["X-Auth-Token"], ShouldEqual, Mask)
+			})
+
+			Convey("and other fields are not changed", func() {
+				So(redacted["IdentityEndpoint"], ShouldEqual, "http://127.0.0.1:5000/v3/")
+				So(redacted["Username"], ShouldEqual, "admin")
+				So(redacted["request"].(map[string]interface{})["tenantName"], ShouldEqual, "admin")
+			})
+
+			Convey("and original fields are not modified", func() {
+				So(fields["Password"], ShouldEqual, "Sup3rS3cret")
+			})
+		})
+
+		Convey("When SnapError is created", func() {
+			serr := New(fmt.Errorf("authentication failed"), fields)
+
+			Convey("Then its fields do not contain credentials", func() {
+				for _, v := range serr.Fields() {
+					So(v, ShouldNotEqual, "Sup3rS3cret")
+					So(v, ShouldNotEqual, "AppS3cret")
+					So(v, ShouldNotEqual, "cefb1b0ba45744488e6ed702db699327")
+				}
+				So(serr.Error(), ShouldEqual, "authentication failed")
+			})
+		})
+
+		Convey("When SnapError is created without fields", func() {
+			serr := New(fmt.Errorf("authentication failed"))
+
+			Convey("Then error is returned", func() {
+				So(serr, ShouldNotBeNil)
+				So(serr.Error(), ShouldEqual, "authentication failed")
+			})
+		})
+	})
+}
@@ -21,6 +21,8 @@ package types
 
 // Tenant represents OpenStack tenant
 type Tenant struct {
-	Name string `json:"name"`
-	ID   string
+	Name     string `json:"name"`
+	ID       string
+	DomainID string
+	ParentID string
 }